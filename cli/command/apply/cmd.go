@@ -0,0 +1,373 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dnephin/cobra"
+	api "github.com/storageos/go-api"
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+	"github.com/storageos/go-cli/pkg/manifest"
+)
+
+type applyOptions struct {
+	files []string
+	prune bool
+}
+
+// NewApplyCommand returns a cobra command for `apply` subcommands
+func NewApplyCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	opt := applyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "apply -f FILENAME [OPTIONS]",
+		Short: "Create or update the objects described by manifests",
+		Long:  applyDescription,
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(opt.files) == 0 {
+				return errors.New("Please provide a manifest with -f")
+			}
+			return runApply(storageosCli, opt)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVarP(&opt.files, "filename", "f", nil, "Manifest file or directory to apply, or - for stdin (repeatable)")
+	flags.BoolVar(&opt.prune, "prune", false, "Remove objects missing from the manifests, for the kinds and namespaces they describe")
+
+	return cmd
+}
+
+func runApply(storageosCli *command.StorageOSCli, opt applyOptions) error {
+	objects, err := manifest.ReadFiles(opt.files, storageosCli.In(), storageosCli.Namespace())
+	if err != nil {
+		return err
+	}
+
+	status := applyObjects(storageosCli.Client(), objects, opt.prune, storageosCli.Out(), storageosCli.Err())
+	if status != 0 {
+		return cli.StatusError{StatusCode: status}
+	}
+	return nil
+}
+
+// applyObjects applies objects in the order of their kinds, writing what
+// was done to out and errors to errOut, and returns the exit status.
+func applyObjects(client *api.Client, objects []*manifest.Object, prune bool, out, errOut io.Writer) int {
+	status := 0
+	var pruned [][]*manifest.Object
+
+	for _, kind := range manifest.Kinds {
+		desired := manifest.ByKind(objects, kind)
+		if len(desired) == 0 {
+			continue
+		}
+
+		live, err := manifest.List(client, kind, manifest.Namespaces(desired))
+		if err != nil {
+			fmt.Fprintf(errOut, "failed to list %s objects: %s\n", kind, err)
+			status = 1
+			continue
+		}
+
+		liveByName := make(map[string]*manifest.Object)
+		for _, o := range live {
+			liveByName[o.String()] = o
+		}
+
+		wanted := make(map[string]bool)
+		for _, o := range desired {
+			wanted[o.String()] = true
+
+			result, err := applyObject(client, o, liveByName[o.String()])
+			if err != nil {
+				fmt.Fprintf(errOut, "%s: %s\n", o, err)
+				status = 1
+				continue
+			}
+			fmt.Fprintf(out, "%s %s\n", o, result)
+		}
+
+		var unwanted []*manifest.Object
+		for _, o := range live {
+			if !wanted[o.String()] && !neverPruned(o) {
+				unwanted = append(unwanted, o)
+			}
+		}
+		pruned = append(pruned, unwanted)
+	}
+
+	if prune {
+		// Remove volumes before the rules, pools and namespaces they may
+		// depend on.
+		for i := len(pruned) - 1; i >= 0; i-- {
+			for _, o := range pruned[i] {
+				if err := deleteObject(client, o); err != nil {
+					fmt.Fprintf(errOut, "%s: %s\n", o, err)
+					status = 1
+					continue
+				}
+				fmt.Fprintf(out, "%s pruned\n", o)
+			}
+		}
+	}
+
+	return status
+}
+
+// neverPruned returns true for the default namespace and pool, and for
+// users and policies. A manifest missing users or policies, such as an
+// export of another cluster, would otherwise remove the accounts of the
+// cluster or their access, including those of the user applying it.
+func neverPruned(o *manifest.Object) bool {
+	if o.Kind == manifest.UserKind || o.Kind == manifest.PolicyKind {
+		return true
+	}
+	return (o.Kind == manifest.NamespaceKind || o.Kind == manifest.PoolKind) && o.Metadata.Name == "default"
+}
+
+// applyObject creates o if it isn't live, or updates the live object to
+// match it, returning what was done.
+func applyObject(client *api.Client, o *manifest.Object, live *manifest.Object) (string, error) {
+	if live == nil {
+		return "created", createObject(client, o)
+	}
+
+	o = manifest.Complete(o, live)
+	if manifest.Equal(o, live) {
+		return "unchanged", nil
+	}
+	return "configured", updateObject(client, o, live)
+}
+
+func createObject(client *api.Client, o *manifest.Object) error {
+	ctx := context.Background()
+
+	switch spec := o.Spec.(type) {
+	case *manifest.NamespaceSpec:
+		_, err := client.NamespaceCreate(types.NamespaceCreateOptions{
+			Name:        o.Metadata.Name,
+			DisplayName: spec.DisplayName,
+			Description: spec.Description,
+			Labels:      o.Metadata.Labels,
+			Context:     ctx,
+		})
+		return err
+
+	case *manifest.PoolSpec:
+		_, err := client.PoolCreate(types.PoolCreateOptions{
+			Name:            o.Metadata.Name,
+			Description:     spec.Description,
+			Default:         spec.Default,
+			DefaultDriver:   spec.DefaultDriver,
+			ControllerNames: spec.ControllerNames,
+			DriverNames:     spec.DriverNames,
+			Active:          spec.Active,
+			Labels:          o.Metadata.Labels,
+			Context:         ctx,
+		})
+		return err
+
+	case *manifest.TemplateSpec:
+		_, err := client.TemplateCreate(types.TemplateCreateOptions{
+			Name:          o.Metadata.Name,
+			Description:   spec.Description,
+			Format:        spec.Format,
+			AutoIncrement: spec.AutoIncrement,
+			Padding:       spec.Padding,
+			PaddingLength: spec.PaddingLength,
+			Active:        spec.Active,
+			Weight:        spec.Weight,
+			ObjectTypes:   spec.ObjectTypes,
+			Labels:        spec.Labels,
+			Context:       ctx,
+		})
+		return err
+
+	case *manifest.RuleSpec:
+		_, err := client.RuleCreate(types.RuleCreateOptions{
+			Name:        o.Metadata.Name,
+			Namespace:   o.Metadata.Namespace,
+			Description: spec.Description,
+			Active:      spec.Active,
+			Weight:      spec.Weight,
+			RuleAction:  spec.Action,
+			Selector:    spec.Selector,
+			Labels:      spec.Labels,
+			Context:     ctx,
+		})
+		return err
+
+	case *manifest.UserSpec:
+		if spec.Password == "" {
+			return errors.New("a spec.password is needed to create the user")
+		}
+		return client.UserCreate(types.UserCreateOptions{
+			Username: o.Metadata.Name,
+			Groups:   spec.Groups,
+			Password: spec.Password,
+			Role:     spec.Role,
+			Context:  ctx,
+		})
+
+	case *manifest.PolicySpec:
+		policy := spec.Policy()
+		data, err := json.Marshal(&policy)
+		if err != nil {
+			return err
+		}
+		return client.PolicyCreate(append(data, '\n'), ctx)
+
+	case *manifest.VolumeSpec:
+		_, err := client.VolumeCreate(types.VolumeCreateOptions{
+			Name:         o.Metadata.Name,
+			Namespace:    o.Metadata.Namespace,
+			Description:  spec.Description,
+			Size:         spec.Size,
+			Pool:         spec.Pool,
+			FSType:       spec.FSType,
+			NodeSelector: spec.NodeSelector,
+			Labels:       o.Metadata.Labels,
+			Context:      ctx,
+		})
+		return err
+	}
+	return fmt.Errorf("unknown kind %q", o.Kind)
+}
+
+// updateObject updates live to match o, which has been completed from it.
+func updateObject(client *api.Client, o *manifest.Object, live *manifest.Object) error {
+	ctx := context.Background()
+
+	switch spec := o.Spec.(type) {
+	case *manifest.NamespaceSpec:
+		_, err := client.NamespaceUpdate(types.NamespaceCreateOptions{
+			Name:        o.Metadata.Name,
+			DisplayName: spec.DisplayName,
+			Description: spec.Description,
+			Labels:      o.Metadata.Labels,
+			Context:     ctx,
+		})
+		return err
+
+	case *manifest.PoolSpec:
+		_, err := client.PoolUpdate(types.PoolCreateOptions{
+			Name:            o.Metadata.Name,
+			Description:     spec.Description,
+			Default:         spec.Default,
+			DefaultDriver:   spec.DefaultDriver,
+			ControllerNames: spec.ControllerNames,
+			DriverNames:     spec.DriverNames,
+			Active:          spec.Active,
+			Labels:          o.Metadata.Labels,
+			Context:         ctx,
+		})
+		return err
+
+	case *manifest.TemplateSpec:
+		return errors.New("templates can't be updated, remove the template to create it again")
+
+	case *manifest.RuleSpec:
+		_, err := client.RuleUpdate(types.RuleUpdateOptions{
+			ID:          live.ID,
+			Name:        o.Metadata.Name,
+			Namespace:   o.Metadata.Namespace,
+			Description: spec.Description,
+			Active:      spec.Active,
+			Weight:      spec.Weight,
+			RuleAction:  spec.Action,
+			Selector:    spec.Selector,
+			Labels:      spec.Labels,
+			Context:     ctx,
+		})
+		return err
+
+	case *manifest.UserSpec:
+		// The password is left unchanged.
+		return client.UserUpdate(&types.User{
+			UUID:     live.ID,
+			Username: o.Metadata.Name,
+			Groups:   spec.Groups,
+			Role:     spec.Role,
+		}, ctx)
+
+	case *manifest.VolumeSpec:
+		l := live.Spec.(*manifest.VolumeSpec)
+		if spec.Pool != l.Pool {
+			return fmt.Errorf("the pool of a volume can't be changed from %s to %s", l.Pool, spec.Pool)
+		}
+		if spec.FSType != l.FSType {
+			return fmt.Errorf("the filesystem of a volume can't be changed from %s to %s", l.FSType, spec.FSType)
+		}
+		_, err := client.VolumeUpdate(types.VolumeUpdateOptions{
+			ID:           live.ID,
+			Name:         o.Metadata.Name,
+			Namespace:    o.Metadata.Namespace,
+			Description:  spec.Description,
+			Size:         spec.Size,
+			NodeSelector: spec.NodeSelector,
+			Labels:       o.Metadata.Labels,
+			Context:      ctx,
+		})
+		return err
+	}
+	// Policies are identified by their spec, so they never need updating.
+	return fmt.Errorf("%s objects can't be updated", o.Kind)
+}
+
+func deleteObject(client *api.Client, o *manifest.Object) error {
+	params := types.DeleteOptions{
+		Name:      o.Metadata.Name,
+		Namespace: o.Metadata.Namespace,
+		Context:   context.Background(),
+	}
+
+	switch o.Kind {
+	case manifest.NamespaceKind:
+		return client.NamespaceDelete(params)
+	case manifest.PoolKind:
+		return client.PoolDelete(params)
+	case manifest.TemplateKind:
+		return client.TemplateDelete(o.Metadata.Name)
+	case manifest.RuleKind:
+		return client.RuleDelete(params)
+	case manifest.UserKind:
+		return client.UserDelete(params)
+	case manifest.PolicyKind:
+		return client.PolicyDelete(types.DeleteOptions{Name: o.ID, Context: params.Context})
+	case manifest.VolumeKind:
+		return client.VolumeDelete(params)
+	}
+	return fmt.Errorf("unknown kind %q", o.Kind)
+}
+
+var applyDescription = `
+Create or update the objects described by manifests, so that applying the
+same manifests again changes nothing. Manifests are YAML or JSON files of
+one or more documents, each describing an object:
+
+  apiVersion: v1
+  kind: Volume
+  metadata:
+    name: db
+    namespace: prod
+    labels:
+      app: postgres
+  spec:
+    size: 20
+
+The kinds are Namespace, Pool, Template, Rule, User, Policy and Volume.
+Policies have no metadata, being identified by their spec. Labels found on
+volumes but missing from their manifest are kept, as rules set labels of
+their own. The spec.password of a user is only used to create it.
+
+With --prune, objects of the kinds described, and in the namespaces of the
+volumes and rules described, are removed when missing from the manifests.
+The default namespace and pool, users and policies are never removed.
+`
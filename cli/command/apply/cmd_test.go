@@ -0,0 +1,248 @@
+package apply
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	api "github.com/storageos/go-api"
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/pkg/manifest"
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+// fakeAPI stores namespaces and volumes, choosing the pool and filesystem
+// of volumes and labelling them as rules would.
+type fakeAPI struct {
+	mu         sync.Mutex
+	namespaces map[string]*types.Namespace
+	volumes    map[string]*types.Volume
+	writes     int
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/version" {
+		fmt.Fprint(w, `{"apiVersion": "1"}`)
+		return
+	}
+	if r.Method != http.MethodGet {
+		f.writes++
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1"), "/"), "/")
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		var list []*types.Namespace
+		for _, ns := range f.namespaces {
+			list = append(list, ns)
+		}
+		json.NewEncoder(w).Encode(list)
+
+	case len(parts) == 1 && r.Method == http.MethodPost, len(parts) == 2 && r.Method == http.MethodPut:
+		var opts types.NamespaceCreateOptions
+		json.NewDecoder(r.Body).Decode(&opts)
+		ns := &types.Namespace{Name: opts.Name, DisplayName: opts.DisplayName, Description: opts.Description, Labels: opts.Labels}
+		if ns.DisplayName == "" {
+			ns.DisplayName = ns.Name
+		}
+		f.namespaces[ns.Name] = ns
+		json.NewEncoder(w).Encode(ns)
+
+	case len(parts) == 3 && r.Method == http.MethodGet:
+		list := []*types.Volume{}
+		for _, vol := range f.volumes {
+			if vol.Namespace == parts[1] {
+				list = append(list, vol)
+			}
+		}
+		json.NewEncoder(w).Encode(list)
+
+	case len(parts) == 3 && r.Method == http.MethodPost:
+		var opts types.VolumeCreateOptions
+		json.NewDecoder(r.Body).Decode(&opts)
+		vol := &types.Volume{ID: opts.Name, Name: opts.Name, Namespace: opts.Namespace, Size: opts.Size, Pool: opts.Pool, FSType: opts.FSType, Labels: map[string]string{"storageos.feature.replicas": "1"}}
+		if vol.Pool == "" {
+			vol.Pool = "default"
+		}
+		if vol.FSType == "" {
+			vol.FSType = "ext4"
+		}
+		for k, v := range opts.Labels {
+			vol.Labels[k] = v
+		}
+		f.volumes[vol.Namespace+"/"+vol.Name] = vol
+		json.NewEncoder(w).Encode(vol)
+
+	case len(parts) == 4 && r.Method == http.MethodPut:
+		var opts types.VolumeUpdateOptions
+		json.NewDecoder(r.Body).Decode(&opts)
+		vol := f.volumes[parts[1]+"/"+parts[3]]
+		vol.Size, vol.Description, vol.Labels = opts.Size, opts.Description, opts.Labels
+		json.NewEncoder(w).Encode(vol)
+
+	case len(parts) == 4 && r.Method == http.MethodDelete:
+		delete(f.volumes, parts[1]+"/"+parts[3])
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+const testManifest = `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: prod
+---
+apiVersion: v1
+kind: Volume
+metadata:
+  name: db
+  namespace: prod
+  labels:
+    app: postgres
+spec:
+  size: %d
+`
+
+// apply applies the manifest, returning the output lines.
+func apply(t *testing.T, client *api.Client, text string, prune bool) []string {
+	objects, err := manifest.Decode([]byte(text), "test.yaml", "default")
+	assert.NilError(t, err)
+
+	var out, errOut bytes.Buffer
+	status := applyObjects(client, objects, prune, &out, &errOut)
+	assert.Equal(t, errOut.String(), "")
+	assert.Equal(t, status, 0)
+	return strings.Split(strings.TrimSpace(out.String()), "\n")
+}
+
+func TestApply(t *testing.T) {
+	fake := &fakeAPI{namespaces: map[string]*types.Namespace{}, volumes: map[string]*types.Volume{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	client, err := api.NewVersionedClient(srv.URL, api.DefaultVersionStr)
+	assert.NilError(t, err)
+
+	results := apply(t, client, fmt.Sprintf(testManifest, 20), false)
+	assert.DeepEqual(t, results, []string{"namespace/prod created", "volume/prod/db created"})
+	assert.Equal(t, fake.writes, 2)
+
+	// Applying again changes nothing, despite the fields chosen by the
+	// server and the labels set by rules.
+	results = apply(t, client, fmt.Sprintf(testManifest, 20), false)
+	assert.DeepEqual(t, results, []string{"namespace/prod unchanged", "volume/prod/db unchanged"})
+	assert.Equal(t, fake.writes, 2)
+
+	results = apply(t, client, fmt.Sprintf(testManifest, 30), false)
+	assert.DeepEqual(t, results, []string{"namespace/prod unchanged", "volume/prod/db configured"})
+	vol := fake.volumes["prod/db"]
+	assert.Equal(t, vol.Size, 30)
+	assert.Equal(t, vol.Labels["storageos.feature.replicas"], "1")
+
+	// Only objects of the kinds and namespaces described are pruned, and
+	// never the default namespace.
+	fake.namespaces["default"] = &types.Namespace{Name: "default"}
+	fake.volumes["prod/old"] = &types.Volume{Name: "old", Namespace: "prod"}
+	fake.volumes["default/other"] = &types.Volume{Name: "other", Namespace: "default"}
+	results = apply(t, client, fmt.Sprintf(testManifest, 30), true)
+	assert.DeepEqual(t, results, []string{"namespace/prod unchanged", "volume/prod/db unchanged", "volume/prod/old pruned"})
+	assert.Equal(t, len(fake.volumes), 2)
+}
+
+func TestNeverPruned(t *testing.T) {
+	for _, tc := range []struct {
+		kind, name string
+		want       bool
+	}{
+		{manifest.NamespaceKind, "default", true},
+		{manifest.NamespaceKind, "prod", false},
+		{manifest.PoolKind, "default", true},
+		{manifest.VolumeKind, "default", false},
+		{manifest.UserKind, "admin", true},
+		{manifest.UserKind, "alice", true},
+		{manifest.PolicyKind, "", true},
+	} {
+		o := &manifest.Object{Kind: tc.kind, Metadata: manifest.Metadata{Name: tc.name}}
+		assert.Equal(t, neverPruned(o), tc.want)
+	}
+}
+
+func TestUpdatePool(t *testing.T) {
+	var updated []types.PoolCreateOptions
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/version":
+			fmt.Fprint(w, `{"apiVersion": "1"}`)
+		case r.Method == http.MethodPut && r.URL.Path == "/v1/pools/default":
+			if user, password, _ := r.BasicAuth(); user != "admin" || password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			var opts types.PoolCreateOptions
+			json.NewDecoder(r.Body).Decode(&opts)
+			updated = append(updated, opts)
+			json.NewEncoder(w).Encode(&types.Pool{Name: opts.Name, ControllerNames: opts.ControllerNames})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	// Hosts are given as tcp:// addresses, as by --host.
+	client, err := api.NewVersionedClient("tcp://"+srv.Listener.Addr().String(), api.DefaultVersionStr)
+	assert.NilError(t, err)
+	client.SetAuth("admin", "secret")
+
+	live := manifest.FromPool(&types.Pool{ID: "p0", Name: "default", ControllerNames: []string{"a"}, Active: true})
+	o := &manifest.Object{
+		APIVersion: manifest.APIVersion,
+		Kind:       manifest.PoolKind,
+		Metadata:   manifest.Metadata{Name: "default"},
+		Spec:       &manifest.PoolSpec{ControllerNames: []string{"b", "c"}, Active: true},
+	}
+
+	result, err := applyObject(client, o, live)
+	assert.NilError(t, err)
+	assert.Equal(t, result, "configured")
+	assert.Equal(t, len(updated), 1)
+	assert.Equal(t, updated[0].Name, "default")
+	assert.DeepEqual(t, updated[0].ControllerNames, []string{"b", "c"})
+
+	o.Metadata.Name = "fast"
+	_, err = applyObject(client, o, live)
+	assert.Equal(t, err, api.ErrNoSuchPool)
+}
+
+func TestApplyPruneKeepsPolicies(t *testing.T) {
+	var deleted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/version":
+			fmt.Fprint(w, `{"apiVersion": "1"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/policies":
+			fmt.Fprint(w, `{"p1": {"spec": {"user": "alice", "namespace": "prod"}}, "p2": {"spec": {"user": "admin"}}}`)
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := api.NewVersionedClient(srv.URL, api.DefaultVersionStr)
+	assert.NilError(t, err)
+
+	// A manifest describing some of the policies leaves the others, such
+	// as those giving the user applying it access, in place.
+	results := apply(t, client, "apiVersion: v1\nkind: Policy\nspec:\n  user: alice\n  namespace: prod\n", true)
+	assert.DeepEqual(t, results, []string{"policy/user=alice,namespace=prod unchanged"})
+	assert.Equal(t, len(deleted), 0)
+}
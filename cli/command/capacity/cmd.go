@@ -0,0 +1,184 @@
+package capacity
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/dnephin/cobra"
+	units "github.com/docker/go-units"
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+	"github.com/storageos/go-cli/cli/command/formatter"
+)
+
+type capacityOptions struct {
+	quiet     bool
+	format    string
+	output    string
+	threshold float64
+}
+
+// NewCapacityCommand returns a cobra command for `capacity`
+func NewCapacityCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	opt := capacityOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "capacity [OPTIONS]",
+		Short: "Report the capacity of pools, nodes, driver instances and namespaces",
+		Long:  capacityDescription,
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCapacity(storageosCli, opt)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opt.quiet, "quiet", "q", false, "Only display names")
+	flags.StringVar(&opt.format, "format", "", "Pretty-print capacity using a Go template")
+	flags.StringVarP(&opt.output, "output", "o", "", formatter.OutputUsage)
+	flags.Float64Var(&opt.threshold, "threshold", 0, "Exit with status 1 if the utilisation of a pool, node or driver instance is above this percentage")
+
+	return cmd
+}
+
+func runCapacity(storageosCli *command.StorageOSCli, opt capacityOptions) error {
+	if opt.threshold < 0 || opt.threshold > 100 {
+		return fmt.Errorf("invalid threshold %v, expected a percentage between 0 and 100", opt.threshold)
+	}
+	output, err := formatter.ParseOutput(opt.output, opt.format, opt.quiet)
+	if err != nil {
+		return err
+	}
+
+	client := storageosCli.Client()
+
+	pools, err := client.PoolList(types.ListOptions{})
+	if err != nil {
+		return err
+	}
+	nodes, err := client.ControllerList(types.ListOptions{})
+	if err != nil {
+		return err
+	}
+	volumes, err := client.VolumeList(types.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	capacities := report(pools, nodes, volumes)
+
+	if output.IsStructured() {
+		err = formatter.WriteOutput(storageosCli.Out(), output, formatter.CapacityKind, capacities)
+	} else {
+		format := opt.format
+		if len(format) == 0 {
+			format = formatter.TableFormatKey
+		}
+		capacityCtx := formatter.Context{
+			Output: storageosCli.Out(),
+			Format: formatter.NewCapacityFormat(format, opt.quiet),
+		}
+		err = formatter.CapacityWrite(capacityCtx, capacities)
+	}
+	if err != nil {
+		return err
+	}
+
+	if opt.threshold > 0 && checkThreshold(storageosCli.Err(), capacities, opt.threshold) {
+		return cli.StatusError{StatusCode: 1}
+	}
+	return nil
+}
+
+// report returns the capacity of each pool, node and driver instance, and
+// the size of the volumes provisioned in each namespace, in that order and
+// sorted by name within each scope.
+func report(pools []*types.Pool, nodes []*types.Controller, volumes []*types.Volume) []formatter.Capacity {
+	var poolCaps, nodeCaps, driverCaps, namespaceCaps []formatter.Capacity
+
+	poolNames := make(map[string]string, len(pools))
+	for _, p := range pools {
+		poolNames[p.ID] = p.Name
+		poolCaps = append(poolCaps, formatter.NewCapacity(formatter.PoolCapacityScope, p.Name, p.CapacityStats))
+	}
+
+	// Driver instances are reported by the nodes they run on, and by their
+	// pools, so each is only counted once.
+	seen := make(map[string]bool)
+	addDriver := func(pool, node, driver string, stats types.CapacityStats) {
+		if name, ok := poolNames[pool]; ok {
+			pool = name
+		}
+		name := pool + "/" + node + "/" + driver
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		driverCaps = append(driverCaps, formatter.NewCapacity(formatter.DriverCapacityScope, name, stats))
+	}
+
+	for _, n := range nodes {
+		nodeCaps = append(nodeCaps, formatter.NewCapacity(formatter.NodeCapacityScope, n.Name, n.CapacityStats))
+		for pool, drivers := range n.PoolStats {
+			for driver, stats := range drivers {
+				addDriver(pool, n.Name, driver, stats)
+			}
+		}
+	}
+	for _, p := range pools {
+		for _, inst := range p.DriverInstances {
+			if inst != nil {
+				addDriver(p.Name, inst.ControllerName, inst.DriverName, inst.CapacityStats)
+			}
+		}
+	}
+
+	provisioned := make(map[string]uint64)
+	for _, v := range volumes {
+		// Volume sizes are in GB, which the API allocates as GiB, the
+		// same unit volume update resizes filesystems to.
+		provisioned[v.Namespace] += uint64(v.Size) * units.GiB
+	}
+	for ns, size := range provisioned {
+		namespaceCaps = append(namespaceCaps, formatter.NewCapacity(formatter.NamespaceCapacityScope, ns, types.CapacityStats{ProvisionedCapacityBytes: size}))
+	}
+
+	var capacities []formatter.Capacity
+	for _, caps := range [][]formatter.Capacity{poolCaps, nodeCaps, driverCaps, namespaceCaps} {
+		sort.Slice(caps, func(i, j int) bool {
+			return caps[i].Name < caps[j].Name
+		})
+		capacities = append(capacities, caps...)
+	}
+	return capacities
+}
+
+// checkThreshold writes a line to out for each capacity used above the
+// threshold percentage, and returns true if there are any. Namespaces have
+// no capacity of their own, and so are never above it.
+func checkThreshold(out io.Writer, capacities []formatter.Capacity, threshold float64) bool {
+	above := false
+	for _, c := range capacities {
+		if c.TotalBytes == 0 || c.Utilisation <= threshold {
+			continue
+		}
+		fmt.Fprintf(out, "%s %s is %.2f%% used, above the threshold of %v%%\n", c.Scope, c.Name, c.Utilisation, threshold)
+		above = true
+	}
+	return above
+}
+
+var capacityDescription = `
+Report the total, available and provisioned capacity of each pool, node and
+driver instance, with the percentage of the capacity used and the
+over-commit ratio, the provisioned capacity over the total. The size of the
+volumes in each namespace is reported as its provisioned capacity.
+
+With --threshold, the exit status is 1 if a pool, node or driver instance
+uses more than that percentage of its capacity, so that the command can be
+used in monitoring checks. Use -o json for dashboards.
+
+  storageos capacity --threshold 80
+`
@@ -0,0 +1,85 @@
+package capacity
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/cli/command/formatter"
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+func stats(total, available, provisioned uint64) types.CapacityStats {
+	return types.CapacityStats{
+		TotalCapacityBytes:       total,
+		AvailableCapacityBytes:   available,
+		ProvisionedCapacityBytes: provisioned,
+	}
+}
+
+func TestReport(t *testing.T) {
+	pools := []*types.Pool{
+		{
+			ID:            "p1",
+			Name:          "fast",
+			CapacityStats: stats(200, 50, 300),
+			DriverInstances: []*types.DriverInstance{
+				{ControllerName: "a", DriverName: "filesystem", CapacityStats: stats(100, 20, 150)},
+				{ControllerName: "b", DriverName: "filesystem", CapacityStats: stats(100, 30, 150)},
+			},
+		},
+		{ID: "p0", Name: "default"},
+	}
+	nodes := []*types.Controller{
+		{
+			Name:          "b",
+			CapacityStats: stats(100, 30, 150),
+			PoolStats: map[string]types.DriverStats{
+				"p1": {"filesystem": stats(100, 30, 150)},
+			},
+		},
+		{Name: "a", CapacityStats: stats(100, 20, 150)},
+	}
+	volumes := []*types.Volume{
+		{Namespace: "prod", Size: 2},
+		{Namespace: "dev", Size: 1},
+		{Namespace: "prod", Size: 3},
+	}
+
+	var names []string
+	for _, c := range report(pools, nodes, volumes) {
+		names = append(names, c.Scope+"/"+c.Name)
+	}
+	assert.DeepEqual(t, names, []string{
+		"pool/default",
+		"pool/fast",
+		"node/a",
+		"node/b",
+		"driver/fast/a/filesystem",
+		"driver/fast/b/filesystem",
+		"namespace/dev",
+		"namespace/prod",
+	})
+
+	capacities := report(pools, nodes, volumes)
+	fast := capacities[1]
+	assert.Equal(t, fast.Utilisation, 75.0)
+	assert.Equal(t, fast.OverCommitRatio, 1.5)
+	assert.Equal(t, capacities[0].Utilisation, 0.0)
+	assert.Equal(t, capacities[7].ProvisionedBytes, uint64(5<<30))
+}
+
+func TestCheckThreshold(t *testing.T) {
+	capacities := []formatter.Capacity{
+		formatter.NewCapacity(formatter.PoolCapacityScope, "fast", stats(200, 50, 300)),
+		formatter.NewCapacity(formatter.NodeCapacityScope, "a", stats(100, 20, 150)),
+		formatter.NewCapacity(formatter.NamespaceCapacityScope, "prod", stats(0, 0, 5000)),
+	}
+
+	var buf bytes.Buffer
+	assert.Equal(t, checkThreshold(&buf, capacities, 90), false)
+	assert.Equal(t, buf.String(), "")
+
+	assert.Equal(t, checkThreshold(&buf, capacities, 78), true)
+	assert.Equal(t, buf.String(), "node a is 80.00% used, above the threshold of 78%\n")
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/url"
 	"os"
+	"strings"
 
 	"github.com/dnephin/cobra"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/storageos/go-cli/cli/config/configfile"
 	cliflags "github.com/storageos/go-cli/cli/flags"
 	"github.com/storageos/go-cli/cli/opts"
+	"github.com/storageos/go-cli/pkg/validation"
 )
 
 // Streams is an interface which exposes the standard input and output streams
@@ -36,6 +38,8 @@ type Cli interface {
 // Instances of the client can be returned from NewStorageOSCli.
 type StorageOSCli struct {
 	configFile      *configfile.ConfigFile
+	contextName     string
+	namespace       string
 	username        string
 	password        string
 	in              *InStream
@@ -89,18 +93,67 @@ func (cli *StorageOSCli) ConfigFile() *configfile.ConfigFile {
 	return cli.configFile
 }
 
+// Namespace returns the namespace used for objects given without one, set by
+// the current context.
+func (cli *StorageOSCli) Namespace() string {
+	if cli.namespace != "" {
+		return cli.namespace
+	}
+	return validation.DefaultNamespace
+}
+
+// SaveLastEndpoint records the endpoint the client ended up using, when it
+// was given several, so that the next command tries it first. The config
+// file is only written when that endpoint changes, and failing to write it
+// is ignored as the endpoint is only a hint.
+func (cli *StorageOSCli) SaveLastEndpoint() {
+	if cli.client == nil || cli.configFile == nil {
+		return
+	}
+	t := failover(cli.client)
+	if t == nil {
+		return
+	}
+
+	endpoint := t.endpoint()
+	last := cli.configFile.LastEndpoint(t.endpoints)
+	if endpoint == last || (last == "" && endpoint == t.endpoints[0]) {
+		return
+	}
+	cli.configFile.SetLastEndpoint(t.endpoints, endpoint)
+	if cli.configFile.Filename != "" {
+		cli.configFile.Save()
+	}
+}
+
+// CurrentContext returns the name of the context in use, if any.
+func (cli *StorageOSCli) CurrentContext() string {
+	return cli.contextName
+}
+
 // Initialize the dockerCli runs initialization that must happen after command
 // line flags are parsed.
 func (cli *StorageOSCli) Initialize(opt *cliflags.ClientOptions) error {
 	cli.configFile = LoadDefaultConfigFile(cli.err)
 
-	var err error
+	name, context, err := resolveContext(opt.Common, cli.configFile)
+	if err != nil {
+		return err
+	}
+	cli.contextName = name
+
 	cli.client, err = NewAPIClientFromFlags(opt.Common, cli.configFile)
 	if err != nil {
 		return err
 	}
 
 	cli.defaultVersion = cli.client.ClientVersion()
+	cli.username, cli.password = getCredentials(currentEndpoint(cli.client), opt.Common, cli.configFile, context)
+
+	if context != nil {
+		cli.namespace = context.Namespace
+		cli.configFile.ApplyFormats(context.Formats)
+	}
 
 	// if opts.Common.TrustKey == "" {
 	// 	cli.keyFile = filepath.Join(cliconfig.Dir(), cliflags.DefaultTrustKeyFile)
@@ -136,16 +189,30 @@ func LoadDefaultConfigFile(err io.Writer) *configfile.ConfigFile {
 	if e != nil {
 		fmt.Fprintf(err, "WARNING: Error loading config file:%v\n", e)
 	}
-	// if !configFile.ContainsAuth() {
-	// 	credentials.DetectDefaultStore(configFile)
-	// }
+	configfile.DetectDefaultStore(configFile)
 	return configFile
 }
 
-// NewAPIClientFromFlags creates a new APIClient from command line flags
+// NewAPIClientFromFlags creates a new APIClient from command line flags.
+// When several endpoints are given, the client starts with the first healthy
+// one, trying the one last used first, and requests move on to the next one
+// when an endpoint can't be reached. SaveLastEndpoint records where they
+// ended up.
 // func NewAPIClientFromFlags(opts *cliflags.CommonOptions, configFile *configfile.ConfigFile) (client.APIClient, error) {
 func NewAPIClientFromFlags(opt *cliflags.CommonOptions, configFile *configfile.ConfigFile) (*api.Client, error) {
-	host, err := getServerHost(opt.Hosts, opt.TLS)
+	_, context, err := resolveContext(opt, configFile)
+	if err != nil {
+		return &api.Client{}, err
+	}
+
+	hosts := opt.Hosts
+	tls := opt.TLS
+	if context != nil {
+		hosts = context.Hosts
+		tls = tls || context.TLS != nil
+	}
+
+	endpoints, err := getServerHosts(hosts, tls)
 	if err != nil {
 		return &api.Client{}, err
 	}
@@ -155,25 +222,65 @@ func NewAPIClientFromFlags(opt *cliflags.CommonOptions, configFile *configfile.C
 		verStr = tmpStr
 	}
 
-	client, err := api.NewVersionedClient(host, verStr)
+	tlsConfig, err := newTLSConfig(opt, context)
 	if err != nil {
 		return &api.Client{}, err
 	}
 
-	var username string
-	var password string
+	client, err := newFailoverClient(endpoints, configFile.LastEndpoint(endpoints), verStr, tlsConfig)
+	if err != nil {
+		return &api.Client{}, err
+	}
+
+	username, password := getCredentials(currentEndpoint(client), opt, configFile, context)
+
+	// The session token cached by login is used rather than the password it
+	// was given, as long as those credentials are still the ones in use.
+	if username != "" && password != "" {
+		host, err := credentialsHost(currentEndpoint(client))
+		if err != nil || !isLoggedIn(configFile, host, username, password) || !useSession(client, verStr, configFile, host, username, password) {
+			client.SetAuth(username, password)
+		}
+	}
+
+	return client, nil
+}
+
+// resolveContext returns the context selected by --context, the
+// STORAGEOS_CONTEXT environment variable or the config file, in that order.
+// No context is used when a host is given with -H or STORAGEOS_HOST instead.
+// With opt.NoContext, only the name of the context is returned.
+func resolveContext(opt *cliflags.CommonOptions, configFile *configfile.ConfigFile) (string, *configfile.Context, error) {
+	name := opt.Context
+	if name != "" && len(opt.Hosts) > 0 {
+		return "", nil, errors.New("Conflicting options: either specify --host or --context, not both")
+	}
+	if name == "" && len(opt.Hosts) == 0 {
+		name = os.Getenv(cliconfig.EnvStorageOSContext)
+		if name == "" && os.Getenv(cliconfig.EnvStorageOSHost) == "" {
+			name = configFile.CurrentContext
+		}
+	}
+	if name == "" || opt.NoContext {
+		return name, nil, nil
+	}
+
+	context, err := configFile.GetContext(name)
+	if err != nil {
+		return "", nil, err
+	}
+	return name, context, nil
+}
 
-	p, err := url.Parse(host)
+// getCredentials returns the username and password to use for host, taking
+// them from the flags first, then the context, the credentials store and
+// finally the environment.
+func getCredentials(host string, opt *cliflags.CommonOptions, configFile *configfile.ConfigFile, context *configfile.Context) (username string, password string) {
+	credHost, err := credentialsHost(host)
 	if err != nil {
 		username = os.Getenv(cliconfig.EnvStorageosUsername)
 		password = os.Getenv(cliconfig.EnvStorageosPassword)
 	} else {
-		port := p.Port()
-		if port == "" {
-			port = api.DefaultPort
-		}
-
-		credHost := fmt.Sprintf("%s:%s", p.Hostname(), port)
 		username, password, err = configFile.CredentialsStore.GetCredentials(credHost)
 		if err != nil {
 			username = os.Getenv(cliconfig.EnvStorageosUsername)
@@ -181,32 +288,56 @@ func NewAPIClientFromFlags(opt *cliflags.CommonOptions, configFile *configfile.C
 		}
 	}
 
+	if context != nil && context.Username != "" && context.Username != username {
+		// The stored password belongs to another user.
+		username, password = context.Username, os.Getenv(cliconfig.EnvStorageosPassword)
+	}
 	if opt.Username != "" {
 		username = opt.Username
 	}
 	if opt.Password != "" {
 		password = opt.Password
 	}
+	return username, password
+}
 
-	if username != "" && password != "" {
-		client.SetAuth(username, password)
+// credentialsHost returns the host:port key under which login stores the
+// credentials of endpoint.
+func credentialsHost(endpoint string) (string, error) {
+	p, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
 	}
+	port := p.Port()
+	if port == "" {
+		port = api.DefaultPort
+	}
+	return fmt.Sprintf("%s:%s", p.Hostname(), port), nil
+}
 
-	return client, nil
+// isLoggedIn returns true if username and password are the credentials
+// stored by login for host.
+func isLoggedIn(configFile *configfile.ConfigFile, host, username, password string) bool {
+	u, p, err := configFile.CredentialsStore.GetCredentials(host)
+	return err == nil && u == username && p == password
 }
 
-func getServerHost(hosts []string, tls bool) (host string, err error) {
-	switch len(hosts) {
-	case 0:
-		host = os.Getenv(cliconfig.EnvStorageOSHost)
-	case 1:
-		host = hosts[0]
-	default:
-		return "", errors.New("Please specify only one -H")
+// getServerHosts returns the endpoints given with -H, or else the comma
+// separated ones of STORAGEOS_HOST.
+func getServerHosts(hosts []string, tls bool) ([]string, error) {
+	if len(hosts) == 0 {
+		hosts = strings.Split(os.Getenv(cliconfig.EnvStorageOSHost), ",")
 	}
 
-	host, err = opts.ParseHost(tls, host)
-	return
+	endpoints := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		endpoint, err := opts.ParseHost(tls, host)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, nil
 }
 
 // Standard alias definitions
@@ -223,3 +354,18 @@ func WithAlias(c *cobra.Command, aliases ...string) *cobra.Command {
 	c.Aliases = append(c.Aliases, aliases...)
 	return c
 }
+
+// NoContextTag tags the commands that don't use the settings of the current
+// context, along with their subcommands.
+const NoContextTag = "nocontext"
+
+// UsesContext returns false if c or one of its parents is tagged with
+// NoContextTag.
+func UsesContext(c *cobra.Command) bool {
+	for ; c != nil; c = c.Parent() {
+		if _, ok := c.Tags[NoContextTag]; ok {
+			return false
+		}
+	}
+	return true
+}
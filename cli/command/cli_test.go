@@ -0,0 +1,32 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/storageos/go-cli/cli/config/configfile"
+	cliflags "github.com/storageos/go-cli/cli/flags"
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+func TestResolveContextNoContext(t *testing.T) {
+	configFile := &configfile.ConfigFile{CurrentContext: "gone"}
+
+	// A current context missing from the config file fails the commands
+	// using it...
+	_, _, err := resolveContext(&cliflags.CommonOptions{}, configFile)
+	assert.Error(t, err, "gone")
+
+	// ...but not those managing contexts, which only need its name.
+	name, context, err := resolveContext(&cliflags.CommonOptions{NoContext: true}, configFile)
+	assert.NilError(t, err)
+	assert.Equal(t, name, "gone")
+	assert.Equal(t, context == nil, true)
+}
+
+func TestNamespace(t *testing.T) {
+	cli := &StorageOSCli{}
+	assert.Equal(t, cli.Namespace(), "default")
+
+	cli.namespace = "prod"
+	assert.Equal(t, cli.Namespace(), "prod")
+}
@@ -20,6 +20,7 @@ type healthOpt struct {
 	cluster string
 	quiet   bool
 	format  string
+	output  string
 	timeout int
 }
 
@@ -41,11 +42,16 @@ func newHealthCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 	flags.BoolVarP(&opt.quiet, "quiet", "q", false, "Display minimal cluster health info.  Can be used with format.")
 	flags.IntVarP(&opt.timeout, "timeout", "t", 1, "Timeout in seconds.")
 	flags.StringVar(&opt.format, "format", "", "Pretty-print health with formats: table (default), cp, dp or raw.")
+	flags.StringVarP(&opt.output, "output", "o", "", formatter.OutputUsage)
 
 	return cmd
 }
 
 func runHealth(storageosCli *command.StorageOSCli, opt *healthOpt) error {
+	output, err := formatter.ParseOutput(opt.output, opt.format, opt.quiet)
+	if err != nil {
+		return err
+	}
 
 	nodes, err := getNodes(storageosCli, opt)
 	if err != nil {
@@ -60,6 +66,9 @@ func runHealth(storageosCli *command.StorageOSCli, opt *healthOpt) error {
 			format = formatter.TableFormatKey
 		}
 	}
+	if output.IsWide() {
+		format = formatter.WideFormatKey
+	}
 
 	for _, node := range nodes {
 		if err := runNodeHealth(storageosCli, node, opt.timeout); err != nil {
@@ -67,6 +76,10 @@ func runHealth(storageosCli *command.StorageOSCli, opt *healthOpt) error {
 		}
 	}
 
+	if output.IsStructured() {
+		return formatter.WriteOutput(storageosCli.Out(), output, formatter.NodeHealthKind, nodes)
+	}
+
 	clusterHealthCtx := formatter.Context{
 		Output: storageosCli.Out(),
 		Format: formatter.NewClusterHealthFormat(format, opt.quiet),
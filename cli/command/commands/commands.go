@@ -4,7 +4,13 @@ import (
 	"fmt"
 	"github.com/dnephin/cobra"
 	"github.com/storageos/go-cli/cli/command"
+	"github.com/storageos/go-cli/cli/command/apply"
+	"github.com/storageos/go-cli/cli/command/capacity"
 	"github.com/storageos/go-cli/cli/command/cluster"
+	clicontext "github.com/storageos/go-cli/cli/command/context"
+	"github.com/storageos/go-cli/cli/command/diff"
+	"github.com/storageos/go-cli/cli/command/event"
+	"github.com/storageos/go-cli/cli/command/export"
 	"github.com/storageos/go-cli/cli/command/login"
 	"github.com/storageos/go-cli/cli/command/logout"
 	"github.com/storageos/go-cli/cli/command/namespace"
@@ -13,6 +19,7 @@ import (
 	"github.com/storageos/go-cli/cli/command/pool"
 	"github.com/storageos/go-cli/cli/command/rule"
 	"github.com/storageos/go-cli/cli/command/system"
+	"github.com/storageos/go-cli/cli/command/template"
 	"github.com/storageos/go-cli/cli/command/user"
 	"github.com/storageos/go-cli/cli/command/volume"
 	"runtime"
@@ -24,16 +31,24 @@ func AddCommands(cmd *cobra.Command, storageosCli *command.StorageOSCli) {
 		command.WithAlias(namespace.NewNamespaceCommand(storageosCli), "ns"),
 		pool.NewPoolCommand(storageosCli),
 		rule.NewRuleCommand(storageosCli),
+		template.NewTemplateCommand(storageosCli),
 		command.WithAlias(user.NewUserCommand(storageosCli), "u"),
 		command.WithAlias(policy.NewPolicyCommand(storageosCli), "pol"),
 		command.WithAlias(volume.NewVolumeCommand(storageosCli), "v", "vol"),
 		command.WithAlias(node.NewNodeCommand(storageosCli), "n"),
 		login.NewLoginCommand(storageosCli),
 		logout.NewLogoutCommand(storageosCli),
+		clicontext.NewContextCommand(storageosCli),
+		apply.NewApplyCommand(storageosCli),
+		diff.NewDiffCommand(storageosCli),
+		export.NewExportCommand(storageosCli),
+		capacity.NewCapacityCommand(storageosCli),
 
 		// system
 		// system.NewSystemCommand(storageosCli),
 		system.NewVersionCommand(storageosCli),
+		system.NewEventsCommand(storageosCli),
+		event.NewEventCommand(storageosCli),
 
 		// clustering
 		command.WithAlias(cluster.NewClusterCommand(storageosCli), "c"),
@@ -0,0 +1,29 @@
+package context
+
+import (
+	"github.com/dnephin/cobra"
+
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+)
+
+// NewContextCommand returns a cobra command for `context` subcommands
+func NewContextCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "context",
+		Short: "Manage the clusters the client connects to",
+		Args:  cli.NoArgs,
+		RunE:  storageosCli.ShowHelp,
+		// The context commands must work when the current context is
+		// broken, to fix it.
+		Tags: map[string]string{command.NoContextTag: ""},
+	}
+	cmd.AddCommand(
+		command.WithAlias(newCreateCommand(storageosCli), command.CreateAliases...),
+		newUseCommand(storageosCli),
+		newCurrentCommand(storageosCli),
+		command.WithAlias(newListCommand(storageosCli), command.ListAliases...),
+		command.WithAlias(newRemoveCommand(storageosCli), command.RemoveAliases...),
+	)
+	return cmd
+}
@@ -0,0 +1,162 @@
+package context
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/dnephin/cobra"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+	"github.com/storageos/go-cli/cli/config/configfile"
+	"github.com/storageos/go-cli/cli/opts"
+)
+
+type createOptions struct {
+	name      string
+	hosts     opts.ListOpts
+	username  string
+	namespace string
+	tls       bool
+	tlsVerify bool
+	insecure  bool
+	caCert    string
+	cert      string
+	key       string
+	formats   opts.ListOpts
+	use       bool
+}
+
+func newCreateCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	opt := createOptions{
+		hosts:   opts.NewListOpts(opts.ValidateHost),
+		formats: opts.NewListOpts(validateFormat),
+	}
+
+	cmd := &cobra.Command{
+		Use:     "create [OPTIONS] CONTEXT",
+		Short:   "Create a context",
+		Long:    createDescription,
+		Example: createExample,
+		Args:    cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opt.name = args[0]
+			return runCreate(storageosCli, opt)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.VarP(&opt.hosts, "host", "H", "Node endpoint(s) of the cluster")
+	flags.StringVarP(&opt.username, "username", "u", "", "API username")
+	flags.StringVarP(&opt.namespace, "namespace", "n", "", `Namespace used when none is given (default "default")`)
+	flags.BoolVar(&opt.tls, "tls", false, "Use TLS; implied by the other TLS options")
+	flags.BoolVar(&opt.tlsVerify, "tlsverify", false, "Use TLS and verify the remote, against the system CAs unless --tlscacert is given")
+	flags.BoolVar(&opt.insecure, "tls-insecure", false, "Use TLS without verifying the remote")
+	flags.StringVar(&opt.caCert, "tlscacert", "", "Trust certs signed only by this CA, enables TLS")
+	flags.StringVar(&opt.cert, "tlscert", "", "Path to TLS certificate file, enables TLS")
+	flags.StringVar(&opt.key, "tlskey", "", "Path to TLS key file, enables TLS")
+	flags.Var(&opt.formats, "format", "Default output format of a list command (e.g. volumes='table {{.Name}}')")
+	flags.BoolVar(&opt.use, "use", false, "Switch to the context once created")
+
+	return cmd
+}
+
+// newContextTLS returns the TLS settings of the context, with absolute paths
+// so that the context works from any directory.
+func newContextTLS(opt createOptions) (*configfile.ContextTLS, error) {
+	tls := &configfile.ContextTLS{Verify: opt.tlsVerify, Insecure: opt.insecure}
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&tls.CACert, opt.caCert},
+		{&tls.Cert, opt.cert},
+		{&tls.Key, opt.key},
+	} {
+		if f.src == "" {
+			continue
+		}
+		path, err := filepath.Abs(f.src)
+		if err != nil {
+			return nil, err
+		}
+		*f.dst = path
+	}
+	return tls, nil
+}
+
+func runCreate(storageosCli *command.StorageOSCli, opt createOptions) error {
+	configFile := storageosCli.ConfigFile()
+
+	if strings.TrimSpace(opt.name) == "" {
+		return fmt.Errorf("context name cannot be empty")
+	}
+	if _, exists := configFile.Contexts[opt.name]; exists {
+		return fmt.Errorf("context %q already exists", opt.name)
+	}
+	if opt.hosts.Len() == 0 {
+		return fmt.Errorf("at least one --host is required")
+	}
+	if opt.insecure && (opt.tlsVerify || opt.caCert != "") {
+		return fmt.Errorf("Conflicting options: --tls-insecure cannot be used with --tlsverify or --tlscacert")
+	}
+
+	context := &configfile.Context{
+		Hosts:     opt.hosts.GetAll(),
+		Username:  opt.username,
+		Namespace: opt.namespace,
+	}
+	if opt.tls || opt.tlsVerify || opt.insecure || opt.caCert != "" || opt.cert != "" || opt.key != "" {
+		tls, err := newContextTLS(opt)
+		if err != nil {
+			return err
+		}
+		context.TLS = tls
+	}
+	if opt.formats.Len() > 0 {
+		context.Formats = &configfile.Formats{}
+		for kind, format := range opts.ConvertKVStringsToMap(opt.formats.GetAll()) {
+			if err := context.Formats.Set(kind, format); err != nil {
+				return err
+			}
+		}
+	}
+
+	if configFile.Contexts == nil {
+		configFile.Contexts = make(map[string]*configfile.Context)
+	}
+	configFile.Contexts[opt.name] = context
+	if opt.use {
+		configFile.CurrentContext = opt.name
+	}
+
+	if err := configFile.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintln(storageosCli.Out(), opt.name)
+	return nil
+}
+
+// validateFormat checks a --format value is given as kind=format.
+func validateFormat(val string) (string, error) {
+	if i := strings.Index(val, "="); i <= 0 {
+		return "", fmt.Errorf("invalid format %q, expected kind=format", val)
+	}
+	return val, nil
+}
+
+var createDescription = `
+Create a named context holding the endpoints of a cluster, the user to log in
+as, the default namespace, TLS files and output formats. Passwords are not
+stored in contexts, use 'storageos login' or STORAGEOS_PASSWORD.
+
+With TLS, the server certificate is verified against --tlscacert, or else the
+system CAs, unless --tls-insecure is given.
+`
+
+var createExample = `
+$ storageos context create --host 10.1.5.249 --username admin --namespace web staging
+staging
+$ storageos context create -H prod1.example.com -H prod2.example.com --tlscacert ca.pem --format volumes='table {{.Name}}\t{{.Size}}' prod
+prod
+`
@@ -0,0 +1,30 @@
+package context
+
+import (
+	"fmt"
+
+	"github.com/dnephin/cobra"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+)
+
+func newCurrentCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "current",
+		Short: "Print the name of the context in use",
+		Long:  currentDescription,
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if name := storageosCli.CurrentContext(); name != "" {
+				fmt.Fprintln(storageosCli.Out(), name)
+			}
+			return nil
+		},
+	}
+}
+
+var currentDescription = `
+Print the name of the context in use, taking --context and the environment
+into account. Nothing is printed when the host is set with -H or
+STORAGEOS_HOST instead.
+`
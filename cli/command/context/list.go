@@ -0,0 +1,62 @@
+package context
+
+import (
+	"github.com/dnephin/cobra"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+	"github.com/storageos/go-cli/cli/command/formatter"
+)
+
+type listOptions struct {
+	quiet  bool
+	format string
+}
+
+func newListCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	opt := listOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List contexts",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(storageosCli, opt)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opt.quiet, "quiet", "q", false, "Only display context names")
+	flags.StringVar(&opt.format, "format", "", "Pretty-print contexts using a Go template")
+
+	return cmd
+}
+
+func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
+	configFile := storageosCli.ConfigFile()
+
+	names := configFile.ContextNames()
+	contexts := make([]formatter.NamedContext, 0, len(names))
+	for _, name := range names {
+		contexts = append(contexts, formatter.NamedContext{
+			Name:    name,
+			Current: name == storageosCli.CurrentContext(),
+			Context: configFile.Contexts[name],
+		})
+	}
+
+	format := opt.format
+	if len(format) == 0 {
+		if len(configFile.ContextsFormat) > 0 && !opt.quiet {
+			format = configFile.ContextsFormat
+		} else {
+			format = formatter.TableFormatKey
+		}
+	}
+
+	contextCtx := formatter.Context{
+		Output: storageosCli.Out(),
+		Format: formatter.NewContextFormat(format, opt.quiet),
+	}
+	return formatter.ContextWrite(contextCtx, contexts)
+}
@@ -0,0 +1,84 @@
+package context
+
+import (
+	"fmt"
+
+	"github.com/dnephin/cobra"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+)
+
+type removeOptions struct {
+	force    bool
+	contexts []string
+}
+
+func newRemoveCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	var opt removeOptions
+
+	cmd := &cobra.Command{
+		Use:     "rm [OPTIONS] CONTEXT [CONTEXT...]",
+		Aliases: []string{"remove"},
+		Short:   "Remove one or more contexts",
+		Long:    removeDescription,
+		Example: removeExample,
+		Args:    cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opt.contexts = args
+			return runRemove(storageosCli, &opt)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opt.force, "force", "f", false, "Remove the current context too")
+
+	return cmd
+}
+
+func runRemove(storageosCli *command.StorageOSCli, opt *removeOptions) error {
+	configFile := storageosCli.ConfigFile()
+	status := 0
+
+	var removed []string
+	for _, name := range opt.contexts {
+		if _, err := configFile.GetContext(name); err != nil {
+			fmt.Fprintf(storageosCli.Err(), "%s\n", err)
+			status = 1
+			continue
+		}
+		if name == configFile.CurrentContext {
+			if !opt.force {
+				fmt.Fprintf(storageosCli.Err(), "context %q is the current context, use --force to remove it\n", name)
+				status = 1
+				continue
+			}
+			configFile.CurrentContext = ""
+		}
+		delete(configFile.Contexts, name)
+		removed = append(removed, name)
+	}
+
+	if len(removed) > 0 {
+		if err := configFile.Save(); err != nil {
+			return err
+		}
+		for _, name := range removed {
+			fmt.Fprintf(storageosCli.Out(), "%s\n", name)
+		}
+	}
+
+	if status != 0 {
+		return cli.StatusError{StatusCode: status}
+	}
+	return nil
+}
+
+var removeDescription = `
+Remove one or more contexts. The current context is only removed with
+--force, after which the host is taken from -H or STORAGEOS_HOST again.
+`
+
+var removeExample = `
+$ storageos context rm staging
+staging
+`
@@ -0,0 +1,48 @@
+package context
+
+import (
+	"fmt"
+
+	"github.com/dnephin/cobra"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+)
+
+func newUseCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	return &cobra.Command{
+		Use:     "use CONTEXT",
+		Short:   "Set the current context",
+		Long:    useDescription,
+		Example: useExample,
+		Args:    cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUse(storageosCli, args[0])
+		},
+	}
+}
+
+func runUse(storageosCli *command.StorageOSCli, name string) error {
+	configFile := storageosCli.ConfigFile()
+
+	if _, err := configFile.GetContext(name); err != nil {
+		return err
+	}
+	configFile.CurrentContext = name
+
+	if err := configFile.Save(); err != nil {
+		return err
+	}
+	fmt.Fprintln(storageosCli.Out(), name)
+	return nil
+}
+
+var useDescription = `
+Set the context used by all following commands. It can be overridden for a
+single command with --context, -H or the STORAGEOS_CONTEXT and STORAGEOS_HOST
+environment variables.
+`
+
+var useExample = `
+$ storageos context use staging
+staging
+`
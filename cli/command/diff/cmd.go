@@ -0,0 +1,189 @@
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dnephin/cobra"
+	"github.com/pmezard/go-difflib/difflib"
+	api "github.com/storageos/go-api"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+	"github.com/storageos/go-cli/pkg/manifest"
+)
+
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+type diffOptions struct {
+	files []string
+	color string
+}
+
+// NewDiffCommand returns a cobra command for `diff` subcommands
+func NewDiffCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	opt := diffOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "diff -f FILENAME [OPTIONS]",
+		Short: "Show the changes applying manifests would make",
+		Long:  diffDescription,
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(opt.files) == 0 {
+				return cli.StatusError{StatusCode: 2, Status: "Please provide a manifest with -f"}
+			}
+			return runDiff(storageosCli, opt)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVarP(&opt.files, "filename", "f", nil, "Manifest file or directory to compare, or - for stdin (repeatable)")
+	flags.StringVar(&opt.color, "color", colorAuto, "Colour the diff: auto, always or never")
+
+	return cmd
+}
+
+func runDiff(storageosCli *command.StorageOSCli, opt diffOptions) error {
+	var color bool
+	switch opt.color {
+	case colorAuto:
+		color = storageosCli.Out().IsTerminal()
+	case colorAlways:
+		color = true
+	case colorNever:
+	default:
+		return cli.StatusError{StatusCode: 2, Status: fmt.Sprintf("invalid --color %q, expected auto, always or never", opt.color)}
+	}
+
+	objects, err := manifest.ReadFiles(opt.files, storageosCli.In(), storageosCli.Namespace())
+	if err != nil {
+		return cli.StatusError{StatusCode: 2, Status: err.Error()}
+	}
+
+	changed, err := diffObjects(storageosCli.Client(), objects, storageosCli.Out(), color)
+	if err != nil {
+		return cli.StatusError{StatusCode: 2, Status: err.Error()}
+	}
+	if changed {
+		return cli.StatusError{StatusCode: 1}
+	}
+	return nil
+}
+
+// diffObjects writes the diff between the live objects and objects, in
+// the order they would be applied, and returns true if there are changes.
+func diffObjects(client *api.Client, objects []*manifest.Object, out io.Writer, color bool) (bool, error) {
+	changed := false
+	for _, kind := range manifest.Kinds {
+		for _, o := range manifest.ByKind(objects, kind) {
+			live, err := manifest.Get(client, o)
+			if err != nil {
+				return changed, fmt.Errorf("%s: %v", o, err)
+			}
+
+			text, err := diffObject(o, live)
+			if err != nil {
+				return changed, fmt.Errorf("%s: %v", o, err)
+			}
+			if text == "" {
+				continue
+			}
+			changed = true
+
+			if color {
+				text = colorize(text)
+			}
+			if _, err := io.WriteString(out, text); err != nil {
+				return changed, err
+			}
+		}
+	}
+	return changed, nil
+}
+
+// diffObject returns the unified diff between live, which may be nil, and
+// the object described by o, leaving out what applying it would not change.
+func diffObject(o *manifest.Object, live *manifest.Object) (string, error) {
+	from := "/dev/null"
+	var a []string
+	if live != nil {
+		o = manifest.Complete(o, live)
+		if manifest.Equal(o, live) {
+			return "", nil
+		}
+
+		text, err := encode(live)
+		if err != nil {
+			return "", err
+		}
+		from = "live/" + o.String()
+		a = splitLines(text)
+	}
+
+	text, err := encode(o)
+	if err != nil {
+		return "", err
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        a,
+		B:        splitLines(text),
+		FromFile: from,
+		ToFile:   "manifest/" + o.String(),
+		Context:  3,
+	})
+}
+
+func encode(o *manifest.Object) (string, error) {
+	var buf bytes.Buffer
+	err := manifest.Encode(&buf, []*manifest.Object{o})
+	return buf.String(), err
+}
+
+// splitLines splits text into lines, keeping their newlines.
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// colorize colours the removed lines of a unified diff red, the added ones
+// green and the hunk headers cyan.
+func colorize(text string) string {
+	lines := strings.SplitAfter(text, "\n")
+	for i, line := range lines {
+		var code string
+		switch {
+		case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+			code = "1"
+		case strings.HasPrefix(line, "@@"):
+			code = "36"
+		case strings.HasPrefix(line, "-"):
+			code = "31"
+		case strings.HasPrefix(line, "+"):
+			code = "32"
+		default:
+			continue
+		}
+		body := strings.TrimSuffix(line, "\n")
+		lines[i] = "\x1b[" + code + "m" + body + "\x1b[0m" + line[len(body):]
+	}
+	return strings.Join(lines, "")
+}
+
+var diffDescription = `
+Show the changes applying manifests would make to the live objects, as a
+unified diff of their manifests. Fields chosen by the server, such as the
+pool of a volume, and labels set on volumes by rules are only shown when
+the manifests set them.
+
+The exit status is 0 when there are no changes, 1 when there are, and 2
+when the manifests or live objects can't be read.
+`
@@ -0,0 +1,63 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/pkg/manifest"
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+const testVolume = `apiVersion: v1
+kind: Volume
+metadata:
+  name: db
+  namespace: prod
+spec:
+  size: 30
+`
+
+func TestDiffObject(t *testing.T) {
+	objects, err := manifest.Decode([]byte(testVolume), "test.yaml", "default")
+	assert.NilError(t, err)
+	o := objects[0]
+
+	live := manifest.FromVolume(&types.Volume{
+		ID:        "9c2b4a3e",
+		Name:      "db",
+		Namespace: "prod",
+		Size:      20,
+		Pool:      "default",
+		FSType:    "ext4",
+		Labels:    map[string]string{"storageos.feature.replicas": "1"},
+		Mounted:   true,
+	})
+
+	text, err := diffObject(o, live)
+	assert.NilError(t, err)
+	assert.Equal(t, text, `--- live/volume/prod/db
++++ manifest/volume/prod/db
+@@ -6,6 +6,6 @@
+   labels:
+     storageos.feature.replicas: "1"
+ spec:
+-  size: 20
++  size: 30
+   pool: default
+   fsType: ext4
+`)
+
+	live.Spec.(*manifest.VolumeSpec).Size = 30
+	text, err = diffObject(o, live)
+	assert.NilError(t, err)
+	assert.Equal(t, text, "")
+
+	text, err = diffObject(o, nil)
+	assert.NilError(t, err)
+	assert.Contains(t, text, "--- /dev/null\n+++ manifest/volume/prod/db\n")
+	assert.Contains(t, text, "+  size: 30\n")
+}
+
+func TestColorize(t *testing.T) {
+	assert.Equal(t, colorize("@@ -1 +1 @@\n-a\n+b\n c\n"), "\x1b[36m@@ -1 +1 @@\x1b[0m\n\x1b[31m-a\x1b[0m\n\x1b[32m+b\x1b[0m\n c\n")
+}
@@ -0,0 +1,23 @@
+package event
+
+import (
+	"github.com/dnephin/cobra"
+
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+)
+
+// NewEventCommand returns a cobra command for `event` subcommands
+func NewEventCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "event",
+		Short: "Inspect past events and tasks",
+		Args:  cli.NoArgs,
+		RunE:  storageosCli.ShowHelp,
+	}
+	cmd.AddCommand(
+		command.WithAlias(newInspectCommand(storageosCli), command.InspectAliases...),
+		command.WithAlias(newListCommand(storageosCli), command.ListAliases...),
+	)
+	return cmd
+}
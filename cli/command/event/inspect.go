@@ -0,0 +1,91 @@
+package event
+
+import (
+	"github.com/dnephin/cobra"
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+	"github.com/storageos/go-cli/cli/command/inspect"
+)
+
+// maxChainDepth guards against parent loops when walking up an event chain.
+const maxChainDepth = 32
+
+// eventChain is an event along with the events that led to it and the
+// events it spawned.
+type eventChain struct {
+	*types.Event
+	Parents  []*types.Event `json:"parents"`
+	Children []*types.Event `json:"children"`
+}
+
+type inspectOptions struct {
+	format string
+	ids    []string
+}
+
+func newInspectCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	var opt inspectOptions
+
+	cmd := &cobra.Command{
+		Use:   "inspect [OPTIONS] EVENT [EVENT...]",
+		Short: "Display detailed information on one or more events, including their parent and child events",
+		Args:  cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opt.ids = args
+			return runInspect(storageosCli, opt)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opt.format, "format", "f", "", "Format the output using the given Go template")
+
+	return cmd
+}
+
+func runInspect(storageosCli *command.StorageOSCli, opt inspectOptions) error {
+	client := storageosCli.Client()
+
+	// Children are only discoverable from the full list, so fetch it once.
+	events, err := client.EventList(types.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	getFunc := func(id string) (interface{}, []byte, error) {
+		event, err := client.Event(id)
+		if err != nil {
+			return nil, nil, err
+		}
+		return getChain(client.Event, events, event), nil, nil
+	}
+
+	return inspect.Inspect(storageosCli.Out(), opt.ids, opt.format, getFunc)
+}
+
+// getChain builds the chain for event. Parents are ordered from the root
+// event down, and a parent that cannot be fetched ends the walk.
+func getChain(getEvent func(string) (*types.Event, error), events []*types.Event, event *types.Event) *eventChain {
+	chain := &eventChain{
+		Event:    event,
+		Parents:  []*types.Event{},
+		Children: []*types.Event{},
+	}
+
+	seen := map[string]bool{event.ID: true}
+	for parent := event.Parent; parent != "" && !seen[parent] && len(chain.Parents) < maxChainDepth; {
+		p, err := getEvent(parent)
+		if err != nil {
+			break
+		}
+		seen[parent] = true
+		chain.Parents = append([]*types.Event{p}, chain.Parents...)
+		parent = p.Parent
+	}
+
+	for _, e := range events {
+		if e.Parent == event.ID && e.ID != event.ID {
+			chain.Children = append(chain.Children, e)
+		}
+	}
+	return chain
+}
@@ -0,0 +1,59 @@
+package event
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/storageos/go-api/types"
+)
+
+func TestGetChain(t *testing.T) {
+	events := []*types.Event{
+		{ID: "root"},
+		{ID: "mid", Parent: "root"},
+		{ID: "leaf", Parent: "mid"},
+		{ID: "sibling", Parent: "mid"},
+		{ID: "loop-a", Parent: "loop-b"},
+		{ID: "loop-b", Parent: "loop-a"},
+	}
+
+	getEvent := func(id string) (*types.Event, error) {
+		for _, e := range events {
+			if e.ID == id {
+				return e, nil
+			}
+		}
+		return nil, errors.New("no such event")
+	}
+
+	chain := getChain(getEvent, events, events[1])
+	if len(chain.Parents) != 1 || chain.Parents[0].ID != "root" {
+		t.Errorf("expected parents [root], got %v", ids(chain.Parents))
+	}
+	if len(chain.Children) != 2 || chain.Children[0].ID != "leaf" || chain.Children[1].ID != "sibling" {
+		t.Errorf("expected children [leaf sibling], got %v", ids(chain.Children))
+	}
+
+	chain = getChain(getEvent, events, events[2])
+	if len(chain.Parents) != 2 || chain.Parents[0].ID != "root" || chain.Parents[1].ID != "mid" {
+		t.Errorf("expected parents [root mid], got %v", ids(chain.Parents))
+	}
+
+	chain = getChain(getEvent, events, events[4])
+	if len(chain.Parents) != 1 || chain.Parents[0].ID != "loop-b" {
+		t.Errorf("expected parent loop to stop after [loop-b], got %v", ids(chain.Parents))
+	}
+
+	chain = getChain(getEvent, events, &types.Event{ID: "orphan", Parent: "missing"})
+	if len(chain.Parents) != 0 {
+		t.Errorf("expected no parents for orphan, got %v", ids(chain.Parents))
+	}
+}
+
+func ids(events []*types.Event) []string {
+	var s []string
+	for _, e := range events {
+		s = append(s, e.ID)
+	}
+	return s
+}
@@ -0,0 +1,140 @@
+package event
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dnephin/cobra"
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+	"github.com/storageos/go-cli/cli/command/formatter"
+	"github.com/storageos/go-cli/cli/opts"
+	"github.com/storageos/go-cli/pkg/validation"
+)
+
+// listFilterKeys are the keys accepted by `event ls --filter`.
+var listFilterKeys = []string{"action", "target", "status", "type", "origin", "parent"}
+
+type listOptions struct {
+	quiet  bool
+	format string
+	since  string
+	until  string
+	filter opts.FilterOpt
+}
+
+func newListCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	opt := listOptions{filter: opts.NewFilterOpt()}
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List past events",
+		Long:    listDescription,
+		Example: listExample,
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(storageosCli, opt)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opt.quiet, "quiet", "q", false, "Only display event IDs")
+	flags.StringVar(&opt.format, "format", "", "Pretty-print events using a Go template")
+	flags.StringVar(&opt.since, "since", "", "Only show events created since timestamp")
+	flags.StringVar(&opt.until, "until", "", "Only show events created before timestamp")
+	flags.VarP(&opt.filter, "filter", "f", "Filter output based on conditions provided (action, target, status, type, origin, parent)")
+
+	return cmd
+}
+
+func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
+	client := storageosCli.Client()
+
+	if err := opt.filter.Validate(listFilterKeys...); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	since, err := opts.ParseTimestamp(opt.since, now)
+	if err != nil {
+		return err
+	}
+	until, err := opts.ParseTimestamp(opt.until, now)
+	if err != nil {
+		return err
+	}
+
+	resolveFilterRefs(storageosCli, &opt.filter)
+
+	events, err := client.EventList(types.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var matched []*types.Event
+	for _, event := range events {
+		t := formatter.EventTime(event)
+		if !since.IsZero() && t.Before(since) {
+			continue
+		}
+		if !until.IsZero() && t.After(until) {
+			continue
+		}
+		if !formatter.MatchEvent(opt.filter, event) {
+			continue
+		}
+		matched = append(matched, event)
+	}
+
+	format := opt.format
+	if len(format) == 0 {
+		if len(storageosCli.ConfigFile().EventsFormat) > 0 && !opt.quiet {
+			format = storageosCli.ConfigFile().EventsFormat
+		} else {
+			format = formatter.TableFormatKey
+		}
+	}
+
+	sort.Sort(formatter.ByEventTime(matched))
+
+	eventCtx := formatter.Context{
+		Output: storageosCli.Out(),
+		Format: formatter.NewEventFormat(format, opt.quiet),
+	}
+	return formatter.EventWrite(eventCtx, matched)
+}
+
+// resolveFilterRefs allows targets to be given as volume references and
+// origins as node names, adding the matching IDs to the filter.
+func resolveFilterRefs(storageosCli *command.StorageOSCli, filter *opts.FilterOpt) {
+	client := storageosCli.Client()
+
+	for _, target := range filter.Value()["target"] {
+		namespace, name, err := validation.ParseRefInNamespace(target, storageosCli.Namespace())
+		if err != nil {
+			continue
+		}
+		if vol, err := client.Volume(namespace, name); err == nil {
+			filter.Set("target=" + vol.ID)
+		}
+	}
+
+	for _, origin := range filter.Value()["origin"] {
+		if node, err := client.Controller(origin); err == nil {
+			filter.Set("origin=" + node.ID)
+		}
+	}
+}
+
+var listDescription = `
+List past events and tasks, oldest first. Targets may be filtered by volume
+reference (namespace/name) or ID, and origins by node name or ID.
+`
+
+var listExample = `
+$ storageos event ls --filter target=default/db01,status=failed
+$ storageos event ls --filter origin=storageos-1 --since 2h
+$ storageos event ls --format "table {{.ID}}\t{{.Action}}\t{{.Deadline}}\t{{.Rollback}}\t{{.Log}}"
+`
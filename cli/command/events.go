@@ -0,0 +1,131 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/storageos/go-api/types"
+)
+
+const (
+	// eventStreamPath is the websocket endpoint publishing cluster events.
+	eventStreamPath = "/v1/ws/event"
+
+	eventStreamHandshakeTimeout = 10 * time.Second
+)
+
+// Events opens a websocket to the configured host and returns a stream of
+// events. The stream is closed by cancelling ctx, after which ctx.Err() is
+// sent over the error channel. Any other failure is sent over the error
+// channel and ends the stream.
+func (cli *StorageOSCli) Events(ctx context.Context) (<-chan types.Request, <-chan error) {
+	messages := make(chan types.Request)
+	errs := make(chan error, 1)
+
+	ws, err := cli.dialEvents()
+	if err != nil {
+		errs <- err
+		return messages, errs
+	}
+
+	// Reading blocks, so unblock it by closing the socket once the caller
+	// is no longer interested.
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			ws.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(time.Second))
+			ws.Close()
+		case <-done:
+		}
+	}()
+
+	go func() {
+		defer close(done)
+		defer ws.Close()
+
+		for {
+			_, message, err := ws.ReadMessage()
+			if err != nil {
+				if ctx.Err() != nil {
+					err = ctx.Err()
+				}
+				errs <- err
+				return
+			}
+
+			var request types.Request
+			if err := json.Unmarshal(message, &request); err != nil {
+				errs <- fmt.Errorf("failed to decode event: %v", err)
+				return
+			}
+
+			select {
+			case messages <- request:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return messages, errs
+}
+
+func (cli *StorageOSCli) dialEvents() (*websocket.Conn, error) {
+	u, err := url.Parse(currentEndpoint(cli.client))
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "tcp", "http", "":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	default:
+		return nil, fmt.Errorf("cannot stream events over %s endpoint %s", u.Scheme, u.Host)
+	}
+	if cli.client.TLSConfig != nil {
+		u.Scheme = "wss"
+	}
+	u.Path = eventStreamPath
+
+	header := http.Header{}
+	if token := sessionToken(cli.client); token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	} else if cli.username != "" && cli.password != "" {
+		req := &http.Request{Header: header}
+		req.SetBasicAuth(cli.username, cli.password)
+	}
+
+	dialer := &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		TLSClientConfig:  cli.client.TLSConfig,
+		HandshakeTimeout: eventStreamHandshakeTimeout,
+	}
+
+	ws, resp, err := dialer.Dial(u.String(), header)
+	if err != nil {
+		if err == websocket.ErrBadHandshake && resp != nil {
+			switch resp.StatusCode {
+			case http.StatusUnauthorized:
+				return nil, errors.New("unauthenticated access to the event stream, please login first")
+			case http.StatusForbidden:
+				return nil, errors.New("your user cannot access the event stream")
+			}
+			return nil, fmt.Errorf("failed to open event stream: %s", resp.Status)
+		}
+		return nil, fmt.Errorf("failed to open event stream: %v", err)
+	}
+	return ws, nil
+}
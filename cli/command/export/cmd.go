@@ -0,0 +1,193 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dnephin/cobra"
+	api "github.com/storageos/go-api"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+	"github.com/storageos/go-cli/pkg/manifest"
+)
+
+type exportOptions struct {
+	filename   string
+	dir        string
+	kinds      []string
+	namespaces []string
+}
+
+// NewExportCommand returns a cobra command for `export` subcommands
+func NewExportCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	opt := exportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "export [OPTIONS]",
+		Short: "Write the configuration of the cluster as manifests",
+		Long:  exportDescription,
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opt.filename != "" && opt.dir != "" {
+				return errors.New("Conflicting options: either specify --filename or --dir, not both")
+			}
+			return runExport(storageosCli, opt)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opt.filename, "filename", "f", "", "Write the manifests to a single file, rather than stdout")
+	flags.StringVar(&opt.dir, "dir", "", "Write the manifests to a directory, one file per kind")
+	flags.StringSliceVar(&opt.kinds, "kind", nil, "Only export objects of this kind (repeatable)")
+	flags.StringSliceVarP(&opt.namespaces, "namespace", "n", nil, "Only export these namespaces, and the rules, volumes and policies in them (repeatable)")
+
+	return cmd
+}
+
+func runExport(storageosCli *command.StorageOSCli, opt exportOptions) error {
+	kinds, err := parseKinds(opt.kinds)
+	if err != nil {
+		return err
+	}
+
+	objects, err := exportObjects(storageosCli.Client(), kinds, opt.namespaces)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case opt.dir != "":
+		return writeDir(opt.dir, objects)
+	case opt.filename != "":
+		return writeFile(opt.filename, objects)
+	}
+	return manifest.Encode(storageosCli.Out(), objects)
+}
+
+// parseKinds returns the kinds named, in the order they are applied, or
+// all the kinds if none are. Names are matched regardless of case.
+func parseKinds(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return manifest.Kinds, nil
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		found := false
+		for _, kind := range manifest.Kinds {
+			if strings.EqualFold(name, kind) {
+				wanted[kind] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown kind %q, expected one of %s", name, strings.Join(manifest.Kinds, ", "))
+		}
+	}
+
+	var kinds []string
+	for _, kind := range manifest.Kinds {
+		if wanted[kind] {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds, nil
+}
+
+// exportObjects returns the live objects of the given kinds, sorted within
+// each kind. If namespaces are given, only those namespaces, and the rules,
+// volumes and policies in them, are returned.
+func exportObjects(client *api.Client, kinds []string, namespaces []string) ([]*manifest.Object, error) {
+	if len(namespaces) == 0 {
+		all, err := manifest.List(client, manifest.NamespaceKind, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list namespaces: %v", err)
+		}
+		for _, ns := range all {
+			namespaces = append(namespaces, ns.Metadata.Name)
+		}
+	}
+
+	selected := make(map[string]bool)
+	for _, ns := range namespaces {
+		selected[ns] = true
+	}
+
+	var objects []*manifest.Object
+	for _, kind := range kinds {
+		list, err := manifest.List(client, kind, namespaces)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s objects: %v", kind, err)
+		}
+
+		var matched []*manifest.Object
+		for _, o := range list {
+			switch spec := o.Spec.(type) {
+			case *manifest.NamespaceSpec:
+				if !selected[o.Metadata.Name] {
+					continue
+				}
+			case *manifest.PolicySpec:
+				if spec.Namespace != "" && !selected[spec.Namespace] {
+					continue
+				}
+			}
+			matched = append(matched, o)
+		}
+		sort.Slice(matched, func(i, j int) bool {
+			return matched[i].String() < matched[j].String()
+		})
+		objects = append(objects, matched...)
+	}
+	return objects, nil
+}
+
+// writeDir writes the objects to dir, creating it if needed, with the
+// objects of each kind in a file named after it, such as volume.yaml.
+func writeDir(dir string, objects []*manifest.Object) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	for _, kind := range manifest.Kinds {
+		objs := manifest.ByKind(objects, kind)
+		if len(objs) == 0 {
+			continue
+		}
+		if err := writeFile(filepath.Join(dir, strings.ToLower(kind)+".yaml"), objs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFile(path string, objects []*manifest.Object) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := manifest.Encode(f, objects); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+var exportDescription = `
+Write the namespaces, pools, templates, rules, users, policies and volumes of
+the cluster as manifests, which can be applied to this or another cluster
+with "storageos apply -f". The runtime state of volumes, such as where they
+are mounted and their replicas, is left out, and so are the passwords of
+users, which must be added as spec.password for apply to create them.
+
+The manifests are written to stdout, to a single file with --filename, or to
+a directory with --dir, holding one file for each kind.
+
+With --namespace, only the given namespaces, and the rules and volumes in
+them, are exported, along with the policies for them and the cluster-wide
+pools, templates and users. Use --kind to choose the kinds exported.
+`
@@ -0,0 +1,97 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	api "github.com/storageos/go-api"
+	"github.com/storageos/go-cli/pkg/manifest"
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+var testResponses = map[string]string{
+	"/version":                       `{"apiVersion": "1"}`,
+	"/v1/namespaces":                 `[{"id": "1", "name": "prod", "displayName": "prod"}, {"id": "2", "name": "default", "displayName": "default"}]`,
+	"/v1/namespaces/prod/volumes":    `[{"id": "3", "name": "db", "namespace": "prod", "size": 20, "pool": "default", "fsType": "ext4", "mounted": true, "master": {"id": "4"}, "replicas": [{"id": "5"}]}]`,
+	"/v1/namespaces/default/volumes": `[{"id": "6", "name": "scratch", "namespace": "default", "size": 5, "pool": "default", "fsType": "ext4"}]`,
+	"/v1/users":                      `[{"id": "7", "username": "alice", "groups": "ops,devs", "role": "admin", "password": "secret"}]`,
+}
+
+func TestExportObjects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := testResponses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+
+	client, err := api.NewVersionedClient(srv.URL, api.DefaultVersionStr)
+	assert.NilError(t, err)
+
+	kinds, err := parseKinds([]string{"volume", "User", "namespace"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, kinds, []string{manifest.NamespaceKind, manifest.UserKind, manifest.VolumeKind})
+
+	objects, err := exportObjects(client, kinds, []string{"prod"})
+	assert.NilError(t, err)
+
+	// Runtime state and passwords are left out.
+	var buf bytes.Buffer
+	assert.NilError(t, manifest.Encode(&buf, objects))
+	assert.Equal(t, buf.String(), `apiVersion: v1
+kind: Namespace
+metadata:
+  name: prod
+spec:
+  displayName: prod
+---
+apiVersion: v1
+kind: User
+metadata:
+  name: alice
+spec:
+  groups:
+  - devs
+  - ops
+  role: admin
+---
+apiVersion: v1
+kind: Volume
+metadata:
+  name: db
+  namespace: prod
+spec:
+  size: 20
+  pool: default
+  fsType: ext4
+`)
+
+	objects, err = exportObjects(client, []string{manifest.VolumeKind}, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(objects), 2)
+	assert.Equal(t, objects[0].String(), "volume/default/scratch")
+
+	dir, err := ioutil.TempDir("", "export")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NilError(t, writeDir(dir, objects))
+	read, err := manifest.ReadFiles([]string{dir}, nil, "default")
+	assert.NilError(t, err)
+	assert.Equal(t, len(read), 2)
+	assert.Equal(t, read[1].Source, filepath.Join(dir, "volume.yaml")+":11")
+}
+
+func TestParseKindsUnknown(t *testing.T) {
+	_, err := parseKinds([]string{"node"})
+	assert.Error(t, err, `unknown kind "node"`)
+}
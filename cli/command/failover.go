@@ -0,0 +1,208 @@
+package command
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	api "github.com/storageos/go-api"
+)
+
+const (
+	// dialTimeout bounds connecting to each endpoint, so that a node that
+	// is down doesn't hold up every command before the next one is tried.
+	dialTimeout = 5 * time.Second
+
+	// pingTimeout bounds the health check of each endpoint.
+	pingTimeout = 2 * time.Second
+)
+
+// newFailoverClient returns an API client for the first healthy endpoint,
+// starting with last when it is one of them. When none answers the client
+// still uses last, or the first endpoint, so that commands that don't need
+// the API work. Requests are retried against the other endpoints on
+// connection errors.
+func newFailoverClient(endpoints []string, last string, version string, tlsConfig *tls.Config) (*api.Client, error) {
+	if len(endpoints) == 1 {
+		return NewVersionedClient(endpoints[0], version, tlsConfig)
+	}
+
+	hosts := make([]string, len(endpoints))
+	start := 0
+	for i, endpoint := range endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme != "tcp" {
+			return nil, errors.New("Multiple endpoints are only supported over tcp")
+		}
+		hosts[i] = u.Host
+		if endpoint == last {
+			start = i
+		}
+	}
+	start = healthyEndpoint(endpoints, start, version, tlsConfig)
+
+	client, err := NewVersionedClient(endpoints[start], version, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	if tr, ok := client.HTTPClient.Transport.(*http.Transport); ok {
+		tr.Dial = (&net.Dialer{Timeout: dialTimeout, KeepAlive: 30 * time.Second}).Dial
+	}
+	client.HTTPClient.Transport = &failoverTransport{
+		next:      client.HTTPClient.Transport,
+		endpoints: endpoints,
+		hosts:     hosts,
+		current:   start,
+	}
+	return client, nil
+}
+
+// healthyEndpoint returns the index of the first of the endpoints, from
+// start, that answers a ping, or start if none can be reached.
+func healthyEndpoint(endpoints []string, start int, version string, tlsConfig *tls.Config) int {
+	for n := range endpoints {
+		i := (start + n) % len(endpoints)
+		client, err := NewVersionedClient(endpoints[i], version, tlsConfig)
+		if err != nil {
+			continue
+		}
+		client.SetTimeout(pingTimeout)
+		// Any answer will do, such as the API asking for credentials.
+		if err := client.Ping(); !isConnectionError(err) {
+			return i
+		}
+	}
+	return start
+}
+
+// failover returns the failover transport of client, if it has one.
+func failover(client *api.Client) *failoverTransport {
+	if client.HTTPClient == nil {
+		return nil
+	}
+	rt := client.HTTPClient.Transport
+	if s, ok := rt.(*sessionTransport); ok {
+		rt = s.next
+	}
+	t, _ := rt.(*failoverTransport)
+	return t
+}
+
+// currentEndpoint returns the endpoint client sends requests to, which
+// changes when it fails over.
+func currentEndpoint(client *api.Client) string {
+	if t := failover(client); t != nil {
+		return t.endpoint()
+	}
+	return client.Endpoint()
+}
+
+// isConnectionError returns true if err means the endpoint couldn't be
+// reached, rather than it answering with an error.
+func isConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if err == api.ErrConnectionRefused {
+		return true
+	}
+	_, ok := err.(net.Error)
+	return ok
+}
+
+// failoverTransport sends requests to the current host, moving on to the
+// next ones when it can't be reached. Idempotent requests are retried on any
+// connection error, others only when the connection couldn't be made, as
+// they may otherwise have been partly processed.
+type failoverTransport struct {
+	next      http.RoundTripper
+	endpoints []string
+	hosts     []string
+
+	mu      sync.Mutex
+	current int
+}
+
+// endpoint returns the endpoint of the current host.
+func (t *failoverTransport) endpoint() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.endpoints[t.current]
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	current := t.current
+	t.mu.Unlock()
+
+	var firstErr error
+	for n := 0; n < len(t.hosts); n++ {
+		i := (current + n) % len(t.hosts)
+
+		r := t.rewrite(req, i)
+		if n > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				break
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(r)
+		if err == nil {
+			t.use(i)
+			return resp, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if req.Context().Err() != nil {
+			return nil, err
+		}
+		if !isDialError(err) && (!isIdempotent(req.Method) || !isConnectionError(err)) {
+			return nil, err
+		}
+	}
+	return nil, firstErr
+}
+
+// use makes the i-th host the current one.
+func (t *failoverTransport) use(i int) {
+	t.mu.Lock()
+	t.current = i
+	t.mu.Unlock()
+}
+
+// rewrite returns a copy of req sent to the i-th host.
+func (t *failoverTransport) rewrite(req *http.Request, i int) *http.Request {
+	r := req.Clone(req.Context())
+	r.URL.Host = t.hosts[i]
+	r.Host = t.hosts[i]
+	return r
+}
+
+// isDialError returns true if err means no connection could be made, so
+// that the request wasn't sent.
+func isDialError(err error) bool {
+	opErr, ok := err.(*net.OpError)
+	return ok && opErr.Op == "dial"
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/storageos/go-cli/cli/config/configfile"
+	cliflags "github.com/storageos/go-cli/cli/flags"
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+// newNodeServer returns an API server answering every request with its name.
+func newNodeServer(name string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/version" {
+			fmt.Fprint(w, `{"apiVersion": "1"}`)
+			return
+		}
+		fmt.Fprint(w, name)
+	}))
+}
+
+func nodeHost(srv *httptest.Server) string {
+	return "tcp://" + strings.TrimPrefix(srv.URL, "http://")
+}
+
+func TestNewAPIClientFromFlagsFailover(t *testing.T) {
+	down := newNodeServer("down")
+	down.Close()
+	node1 := newNodeServer("node1")
+	defer node1.Close()
+	node2 := newNodeServer("node2")
+	defer node2.Close()
+
+	hosts := []string{nodeHost(down), nodeHost(node1), nodeHost(node2)}
+
+	// The client starts with the first healthy endpoint, which is only
+	// recorded once the command is done, and only when it isn't the first.
+	configFile := &configfile.ConfigFile{}
+	client, err := NewAPIClientFromFlags(&cliflags.CommonOptions{Hosts: hosts}, configFile)
+	assert.NilError(t, err)
+	assert.Equal(t, client.Endpoint(), nodeHost(node1))
+	assert.NilError(t, client.Ping())
+	assert.Equal(t, configFile.LastEndpoint(hosts), "")
+
+	cli := &StorageOSCli{client: client, configFile: configFile}
+	cli.SaveLastEndpoint()
+	assert.Equal(t, configFile.LastEndpoint(hosts), nodeHost(node1))
+
+	// The last endpoint used is tried first.
+	configFile.SetLastEndpoint(hosts, nodeHost(node2))
+	client, err = NewAPIClientFromFlags(&cliflags.CommonOptions{Hosts: hosts}, configFile)
+	assert.NilError(t, err)
+	assert.Equal(t, client.Endpoint(), nodeHost(node2))
+
+	// Requests move on when the current endpoint goes down, and so does
+	// the endpoint reported to those that connect on their own.
+	node2.Close()
+	assert.NilError(t, client.Ping())
+	assert.Equal(t, currentEndpoint(client), nodeHost(node1))
+
+	// Other lists of endpoints have their own last endpoint.
+	assert.Equal(t, configFile.LastEndpoint(hosts[1:]), "")
+
+	// Commands that don't need the API work with endpoints that are down.
+	client, err = NewAPIClientFromFlags(&cliflags.CommonOptions{Hosts: []string{nodeHost(down), nodeHost(down)}}, &configfile.ConfigFile{})
+	assert.NilError(t, err)
+	assert.Equal(t, client.Endpoint(), nodeHost(down))
+	assert.Error(t, client.Ping(), "cannot connect")
+}
+
+func TestFailoverTransport(t *testing.T) {
+	node1 := newNodeServer("node1")
+	node2 := newNodeServer("node2")
+	defer node2.Close()
+
+	tr := &failoverTransport{
+		next:  http.DefaultTransport,
+		hosts: []string{node1.Listener.Addr().String(), node2.Listener.Addr().String()},
+	}
+	client := &http.Client{Transport: tr}
+
+	send := func(method string) (string, error) {
+		req, err := http.NewRequest(method, node1.URL+"/v1/volumes", strings.NewReader("{}"))
+		assert.NilError(t, err)
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	name, err := send(http.MethodGet)
+	assert.NilError(t, err)
+	assert.Equal(t, name, "node1")
+
+	node1.Close()
+
+	// Requests that can't be retried still move on when node1 refuses the
+	// connection, as they haven't been sent.
+	name, err = send(http.MethodPost)
+	assert.NilError(t, err)
+	assert.Equal(t, name, "node2")
+
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		name, err = send(method)
+		assert.NilError(t, err)
+		assert.Equal(t, name, "node2")
+	}
+}
@@ -0,0 +1,150 @@
+package formatter
+
+import (
+	"fmt"
+
+	units "github.com/docker/go-units"
+	"github.com/storageos/go-api/types"
+)
+
+const (
+	defaultCapacityQuietFormat = "{{.Scope}}/{{.Name}}"
+	defaultCapacityTableFormat = "table {{.Scope}}\t{{.Name}}\t{{.Total}}\t{{.Available}}\t{{.Provisioned}}\t{{.Used}}\t{{.OverCommit}}"
+
+	capacityScopeHeader       = "SCOPE"
+	capacityNameHeader        = "NAME"
+	capacityTotalHeader       = "TOTAL"
+	capacityAvailableHeader   = "AVAILABLE"
+	capacityProvisionedHeader = "PROVISIONED"
+	capacityUsedHeader        = "USED"
+	capacityOverCommitHeader  = "OVERCOMMIT"
+)
+
+// Scopes of capacity reports
+const (
+	PoolCapacityScope      = "pool"
+	NodeCapacityScope      = "node"
+	DriverCapacityScope    = "driver"
+	NamespaceCapacityScope = "namespace"
+)
+
+// Capacity is the capacity of a pool, node or driver instance, or the
+// capacity provisioned in a namespace, which has no total or available
+// capacity of its own.
+type Capacity struct {
+	Scope            string  `json:"scope"`
+	Name             string  `json:"name"`
+	TotalBytes       uint64  `json:"totalBytes"`
+	AvailableBytes   uint64  `json:"availableBytes"`
+	ProvisionedBytes uint64  `json:"provisionedBytes"`
+	Utilisation      float64 `json:"utilisation"`
+	OverCommitRatio  float64 `json:"overCommitRatio"`
+}
+
+// NewCapacity returns the capacity of the named object of the given scope,
+// with its utilisation, as a percentage of the total capacity in use, and
+// its over-commit ratio, the provisioned capacity over the total.
+func NewCapacity(scope, name string, stats types.CapacityStats) Capacity {
+	c := Capacity{
+		Scope:            scope,
+		Name:             name,
+		TotalBytes:       stats.TotalCapacityBytes,
+		AvailableBytes:   stats.AvailableCapacityBytes,
+		ProvisionedBytes: stats.ProvisionedCapacityBytes,
+	}
+	if c.TotalBytes > 0 {
+		used := uint64(0)
+		if c.TotalBytes > c.AvailableBytes {
+			used = c.TotalBytes - c.AvailableBytes
+		}
+		c.Utilisation = float64(used) * 100 / float64(c.TotalBytes)
+		c.OverCommitRatio = float64(c.ProvisionedBytes) / float64(c.TotalBytes)
+	}
+	return c
+}
+
+// NewCapacityFormat returns a format for use with a capacity Context
+func NewCapacityFormat(source string, quiet bool) Format {
+	switch source {
+	case TableFormatKey, WideFormatKey:
+		if quiet {
+			return defaultCapacityQuietFormat
+		}
+		return defaultCapacityTableFormat
+	case RawFormatKey:
+		if quiet {
+			return `name: {{.Scope}}/{{.Name}}`
+		}
+		return `scope: {{.Scope}}\nname: {{.Name}}\ntotal: {{.Total}}\navailable: {{.Available}}\nprovisioned: {{.Provisioned}}\nused: {{.Used}}\novercommit: {{.OverCommit}}\n`
+	}
+	return Format(source)
+}
+
+// CapacityWrite writes formatted capacities using the Context
+func CapacityWrite(ctx Context, capacities []Capacity) error {
+	render := func(format func(subContext subContext) error) error {
+		for _, c := range capacities {
+			if err := format(&capacityContext{v: c}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return ctx.Write(&capacityContext{}, render)
+}
+
+type capacityContext struct {
+	HeaderContext
+	v Capacity
+}
+
+func (c *capacityContext) MarshalJSON() ([]byte, error) {
+	return marshalJSON(c)
+}
+
+func (c *capacityContext) Scope() string {
+	c.AddHeader(capacityScopeHeader)
+	return c.v.Scope
+}
+
+func (c *capacityContext) Name() string {
+	c.AddHeader(capacityNameHeader)
+	return c.v.Name
+}
+
+func (c *capacityContext) Total() string {
+	c.AddHeader(capacityTotalHeader)
+	if c.v.Scope == NamespaceCapacityScope {
+		return "-"
+	}
+	return units.HumanSize(float64(c.v.TotalBytes))
+}
+
+func (c *capacityContext) Available() string {
+	c.AddHeader(capacityAvailableHeader)
+	if c.v.Scope == NamespaceCapacityScope {
+		return "-"
+	}
+	return units.HumanSize(float64(c.v.AvailableBytes))
+}
+
+func (c *capacityContext) Provisioned() string {
+	c.AddHeader(capacityProvisionedHeader)
+	return units.HumanSize(float64(c.v.ProvisionedBytes))
+}
+
+func (c *capacityContext) Used() string {
+	c.AddHeader(capacityUsedHeader)
+	if c.v.TotalBytes == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f%%", c.v.Utilisation)
+}
+
+func (c *capacityContext) OverCommit() string {
+	c.AddHeader(capacityOverCommitHeader)
+	if c.v.TotalBytes == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", c.v.OverCommitRatio)
+}
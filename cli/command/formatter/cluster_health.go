@@ -10,6 +10,7 @@ import (
 const (
 	defaultClusterHealthQuietFormat = "{{.Status}}"
 	defaultClusterHealthTableFormat = "table {{.Node}}\t{{.Address}}\t{{.Status}}\t{{.KV}}\t{{.NATS}}\t{{.Scheduler}}\t{{.DirectFSClient}}\t{{.DirectFSServer}}\t{{.Director}}\t{{.FSDriver}}\t{{.FS}}"
+	wideClusterHealthTableFormat    = "table {{.Node}}\t{{.Address}}\t{{.Status}}\t{{.CPStatus}}\t{{.DPStatus}}\t{{.KV}}\t{{.KVWrite}}\t{{.NATS}}\t{{.Scheduler}}\t{{.DirectFSClient}}\t{{.DirectFSServer}}\t{{.Director}}\t{{.FSDriver}}\t{{.FS}}"
 	cpClusterHealthTableFormat      = "table {{.Node}}\t{{.Address}}\t{{.Status}}\t{{.KV}}\t{{.KVWrite}}\t{{.NATS}}\t{{.Scheduler}}"
 	dpClusterHealthTableFormat      = "table {{.Node}}\t{{.Address}}\t{{.Status}}\t{{.DirectFSClient}}\t{{.DirectFSServer}}\t{{.Director}}\t{{.FSDriver}}\t{{.FS}}"
 
@@ -47,6 +48,11 @@ func NewClusterHealthFormat(source string, quiet bool) Format {
 			return `{{.DPStatus}}`
 		}
 		return dpClusterHealthTableFormat
+	case WideFormatKey:
+		if quiet {
+			return defaultClusterHealthQuietFormat
+		}
+		return wideClusterHealthTableFormat
 	case RawFormatKey:
 		if quiet {
 			return `{{.Node}}: {{.Status}}`
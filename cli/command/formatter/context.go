@@ -0,0 +1,104 @@
+package formatter
+
+import (
+	"strings"
+
+	"github.com/storageos/go-cli/cli/config/configfile"
+)
+
+const (
+	defaultContextQuietFormat = "{{.Name}}"
+	defaultContextTableFormat = "table {{.Current}}\t{{.Name}}\t{{.Hosts}}\t{{.Username}}\t{{.Namespace}}\t{{.TLS}}"
+
+	contextCurrentHeader   = "CURRENT"
+	contextNameHeader      = "NAME"
+	contextHostsHeader     = "HOSTS"
+	contextUsernameHeader  = "USERNAME"
+	contextNamespaceHeader = "NAMESPACE"
+	contextTLSHeader       = "TLS"
+)
+
+// NamedContext is a context along with its name and whether it is in use.
+type NamedContext struct {
+	Name    string
+	Current bool
+	*configfile.Context
+}
+
+// NewContextFormat returns a format for use with a context Context
+func NewContextFormat(source string, quiet bool) Format {
+	switch source {
+	case TableFormatKey:
+		if quiet {
+			return defaultContextQuietFormat
+		}
+		return defaultContextTableFormat
+	case RawFormatKey:
+		if quiet {
+			return `name: {{.Name}}`
+		}
+		return `name: {{.Name}}\nhosts: {{.Hosts}}\nusername: {{.Username}}\nnamespace: {{.Namespace}}\n`
+	}
+	return Format(source)
+}
+
+// ContextWrite writes formatted contexts using the Context
+func ContextWrite(ctx Context, contexts []NamedContext) error {
+	render := func(format func(subContext subContext) error) error {
+		for _, c := range contexts {
+			if err := format(&contextContext{v: c}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return ctx.Write(&contextContext{}, render)
+}
+
+type contextContext struct {
+	HeaderContext
+	v NamedContext
+}
+
+func (c *contextContext) MarshalJSON() ([]byte, error) {
+	return marshalJSON(c)
+}
+
+func (c *contextContext) Current() string {
+	c.AddHeader(contextCurrentHeader)
+	if c.v.Current {
+		return "*"
+	}
+	return ""
+}
+
+func (c *contextContext) Name() string {
+	c.AddHeader(contextNameHeader)
+	return c.v.Name
+}
+
+func (c *contextContext) Hosts() string {
+	c.AddHeader(contextHostsHeader)
+	return strings.Join(c.v.Context.Hosts, ",")
+}
+
+func (c *contextContext) Username() string {
+	c.AddHeader(contextUsernameHeader)
+	return c.v.Context.Username
+}
+
+func (c *contextContext) Namespace() string {
+	c.AddHeader(contextNamespaceHeader)
+	if c.v.Context.Namespace == "" {
+		return "default"
+	}
+	return c.v.Context.Namespace
+}
+
+func (c *contextContext) TLS() string {
+	c.AddHeader(contextTLSHeader)
+	if c.v.Context.TLS != nil {
+		return "true"
+	}
+	return "false"
+}
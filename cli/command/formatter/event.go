@@ -0,0 +1,190 @@
+package formatter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/cli/opts"
+)
+
+// EventTime returns the time an event was raised, falling back to its
+// creation time for events that were not timestamped.
+func EventTime(event *types.Event) time.Time {
+	if event.Timestamp != 0 {
+		return time.Unix(0, event.Timestamp)
+	}
+	return event.CreatedAt
+}
+
+// ByEventTime sorts events by the time they were raised, oldest first.
+type ByEventTime []*types.Event
+
+func (e ByEventTime) Len() int      { return len(e) }
+func (e ByEventTime) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e ByEventTime) Less(i, j int) bool {
+	return EventTime(e[i]).Before(EventTime(e[j]))
+}
+
+// MatchEvent returns true if the event is accepted by every key of the
+// filter. Keys the filter doesn't set match any event.
+func MatchEvent(filter opts.FilterOpt, event *types.Event) bool {
+	return filter.Match("action", event.Action) &&
+		filter.Match("target", event.Target) &&
+		filter.Match("status", event.Status) &&
+		filter.Match("type", string(event.EventType)) &&
+		filter.Match("origin", event.OriginController) &&
+		filter.Match("parent", event.Parent)
+}
+
+const (
+	defaultEventQuietFormat = "{{.ID}}"
+	defaultEventTableFormat = "table {{.ID}}\t{{.Time}}\t{{.Action}}\t{{.Target}}\t{{.Status}}\t{{.Progress}}\t{{.Attempts}}"
+
+	eventIDHeader        = "ID"
+	eventParentHeader    = "PARENT"
+	eventTimeHeader      = "TIME"
+	eventTypeHeader      = "TYPE"
+	eventActionHeader    = "ACTION"
+	eventTargetHeader    = "TARGET"
+	eventStatusHeader    = "STATUS"
+	eventMessageHeader   = "MESSAGE"
+	eventProgressHeader  = "DONE"
+	eventAttemptsHeader  = "ATTEMPTS"
+	eventDeadlineHeader  = "DEADLINE"
+	eventRollbackHeader  = "ROLLBACK"
+	eventLogHeader       = "LOG"
+	eventOriginHeader    = "ORIGIN"
+	eventCreatedByHeader = "CREATED BY"
+
+	eventTimeFormat = "2006-01-02T15:04:05"
+)
+
+// NewEventFormat returns a format for use with an event Context
+func NewEventFormat(source string, quiet bool) Format {
+	switch source {
+	case TableFormatKey:
+		if quiet {
+			return defaultEventQuietFormat
+		}
+		return defaultEventTableFormat
+	case RawFormatKey:
+		if quiet {
+			return `id: {{.ID}}`
+		}
+		return `id: {{.ID}}\nparent: {{.Parent}}\ntime: {{.Time}}\naction: {{.Action}}\ntarget: {{.Target}}\nstatus: {{.Status}}\nprogress: {{.Progress}}\nattempts: {{.Attempts}}\ndeadline: {{.Deadline}}\nrollback: {{.Rollback}}\nlog: {{.Log}}\n`
+	}
+	return Format(source)
+}
+
+// EventWrite writes formatted events using the Context
+func EventWrite(ctx Context, events []*types.Event) error {
+	render := func(format func(subContext subContext) error) error {
+		for _, event := range events {
+			if err := format(&eventContext{v: *event}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return ctx.Write(&eventContext{}, render)
+}
+
+type eventContext struct {
+	HeaderContext
+	v types.Event
+}
+
+func (c *eventContext) MarshalJSON() ([]byte, error) {
+	return marshalJSON(c)
+}
+
+func (c *eventContext) ID() string {
+	c.AddHeader(eventIDHeader)
+	return c.v.ID
+}
+
+func (c *eventContext) Parent() string {
+	c.AddHeader(eventParentHeader)
+	return c.v.Parent
+}
+
+func (c *eventContext) Time() string {
+	c.AddHeader(eventTimeHeader)
+	t := EventTime(&c.v)
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(eventTimeFormat)
+}
+
+func (c *eventContext) Type() string {
+	c.AddHeader(eventTypeHeader)
+	return string(c.v.EventType)
+}
+
+func (c *eventContext) Action() string {
+	c.AddHeader(eventActionHeader)
+	return c.v.Action
+}
+
+func (c *eventContext) Target() string {
+	c.AddHeader(eventTargetHeader)
+	return c.v.Target
+}
+
+func (c *eventContext) Status() string {
+	c.AddHeader(eventStatusHeader)
+	return c.v.Status
+}
+
+func (c *eventContext) Message() string {
+	c.AddHeader(eventMessageHeader)
+	return c.v.Message
+}
+
+func (c *eventContext) Progress() string {
+	c.AddHeader(eventProgressHeader)
+	return fmt.Sprintf("%d%%", c.v.ProgressPercent)
+}
+
+func (c *eventContext) Attempts() string {
+	c.AddHeader(eventAttemptsHeader)
+	return strconv.Itoa(c.v.Attempts)
+}
+
+func (c *eventContext) Deadline() string {
+	c.AddHeader(eventDeadlineHeader)
+	if c.v.Deadline.IsZero() {
+		return "-"
+	}
+	return c.v.Deadline.Local().Format(eventTimeFormat)
+}
+
+func (c *eventContext) Rollback() string {
+	c.AddHeader(eventRollbackHeader)
+	if len(c.v.Rollback) == 0 {
+		return "-"
+	}
+	if c.v.RollbackDone {
+		return fmt.Sprintf("%d (done)", len(c.v.Rollback))
+	}
+	return strconv.Itoa(len(c.v.Rollback))
+}
+
+func (c *eventContext) Log() string {
+	c.AddHeader(eventLogHeader)
+	return strings.Join(c.v.Log, "; ")
+}
+
+func (c *eventContext) Origin() string {
+	c.AddHeader(eventOriginHeader)
+	return c.v.OriginController
+}
+
+func (c *eventContext) CreatedBy() string {
+	c.AddHeader(eventCreatedByHeader)
+	return c.v.CreatedBy
+}
@@ -10,6 +10,7 @@ import (
 const (
 	defaultNamespaceQuietFormat = "{{.Name}}"
 	defaultNamespaceTableFormat = "table {{.Name}}\t{{.DisplayName}}\t{{.Description}}"
+	wideNamespaceTableFormat    = "table {{.Name}}\t{{.DisplayName}}\t{{.Description}}\t{{.Labels}}"
 
 	namespaceNameHeader        = "NAMESPACE"
 	namespaceDisplayNameHeader = "DISPLAY"
@@ -24,6 +25,8 @@ func NewNamespaceFormat(source string, quiet bool) Format {
 			return defaultNamespaceQuietFormat
 		}
 		return defaultNamespaceTableFormat
+	case WideFormatKey:
+		return wideNamespaceTableFormat
 	case RawFormatKey:
 		if quiet {
 			return `name: {{.Name}}`
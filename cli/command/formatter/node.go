@@ -13,7 +13,9 @@ import (
 const (
 	defaultNodeQuietFormat = "{{.Name}}"
 	defaultNodeTableFormat = "table {{.Name}}\t{{.Address}}\t{{.Health}}\t{{.Scheduler}}\t{{.Volumes}}\t{{.Capacity}}\t{{.CapacityUsed}}\t{{.Version}}\t{{.Labels}}"
+	wideNodeTableFormat    = "table {{.ID}}\t{{.Name}}\t{{.Address}}\t{{.Health}}\t{{.Scheduler}}\t{{.Volumes}}\t{{.Capacity}}\t{{.CapacityUsed}}\t{{.Version}}\t{{.Labels}}"
 
+	nodeIDHeader            = "ID"
 	nodeNameHeader          = "NAME"
 	nodeAddressHeader       = "ADDRESS"
 	nodeHealthHeader        = "HEALTH"
@@ -33,6 +35,8 @@ func NewNodeFormat(source string, quiet bool) Format {
 			return defaultNodeQuietFormat
 		}
 		return defaultNodeTableFormat
+	case WideFormatKey:
+		return wideNodeTableFormat
 	case RawFormatKey:
 		if quiet {
 			return `name: {{.Name}}`
@@ -64,6 +68,11 @@ func (c *nodeContext) MarshalJSON() ([]byte, error) {
 	return marshalJSON(c)
 }
 
+func (c *nodeContext) ID() string {
+	c.AddHeader(nodeIDHeader)
+	return c.v.ID
+}
+
 func (c *nodeContext) Name() string {
 	c.AddHeader(nodeNameHeader)
 	return c.v.Name
@@ -29,7 +29,7 @@ const (
 // NewNodeHealthFormat returns a format for use with a node health Context
 func NewNodeHealthFormat(source string, quiet bool) Format {
 	switch source {
-	case TableFormatKey:
+	case TableFormatKey, WideFormatKey:
 		if quiet {
 			return defaultClusterHealthQuietFormat
 		}
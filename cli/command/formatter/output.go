@@ -0,0 +1,222 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/pkg/jsonpath"
+	cliTypes "github.com/storageos/go-cli/types"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Output keys accepted by -o
+const (
+	JSONOutputKey     = "json"
+	YAMLOutputKey     = "yaml"
+	JSONPathOutputKey = "jsonpath"
+	WideOutputKey     = "wide"
+	NameOutputKey     = "name"
+
+	// WideFormatKey selects the table format with all columns.
+	WideFormatKey = "wide"
+
+	// OutputUsage is the help of the -o flag.
+	OutputUsage = "Output format: json, yaml, jsonpath=TEMPLATE, wide or name"
+
+	// OutputAPIVersion is the version of the envelope of structured output.
+	OutputAPIVersion = "v1"
+)
+
+// Kinds of the objects written as structured output
+const (
+	VolumeKind     = "Volume"
+	NodeKind       = "Node"
+	PoolKind       = "Pool"
+	RuleKind       = "Rule"
+	NamespaceKind  = "Namespace"
+	UserKind       = "User"
+	PolicyKind     = "Policy"
+	NodeHealthKind = "NodeHealth"
+	CapacityKind   = "Capacity"
+)
+
+// Output is an output selected with -o.
+type Output struct {
+	Key      string
+	JSONPath *jsonpath.Template
+}
+
+// ParseOutput parses the value of -o, which can't be combined with --format
+// or --quiet.
+func ParseOutput(output string, format string, quiet bool) (Output, error) {
+	if output == "" {
+		return Output{}, nil
+	}
+	if format != "" || quiet {
+		return Output{}, errors.New("Conflicting options: --output cannot be used with --format or --quiet")
+	}
+
+	key, tmpl := output, ""
+	if i := strings.IndexByte(output, '='); i >= 0 {
+		key, tmpl = output[:i], output[i+1:]
+	}
+
+	switch key {
+	case JSONOutputKey, YAMLOutputKey, WideOutputKey, NameOutputKey:
+		if key != output {
+			return Output{}, fmt.Errorf("output %s takes no template", key)
+		}
+		return Output{Key: key}, nil
+	case JSONPathOutputKey:
+		if tmpl == "" {
+			return Output{}, errors.New("output jsonpath requires a template, e.g. jsonpath='{.items[*].name}'")
+		}
+		t, err := jsonpath.Parse(tmpl)
+		if err != nil {
+			return Output{}, fmt.Errorf("invalid jsonpath template: %v", err)
+		}
+		return Output{Key: key, JSONPath: t}, nil
+	}
+	return Output{}, fmt.Errorf("unknown output %q, expected one of json, yaml, jsonpath=TEMPLATE, wide or name", output)
+}
+
+// IsStructured returns true if the output is written by WriteOutput rather
+// than as a table.
+func (o Output) IsStructured() bool {
+	return o.Key != "" && o.Key != WideOutputKey
+}
+
+// IsWide returns true if the wide table format was selected.
+func (o Output) IsWide() bool {
+	return o.Key == WideOutputKey
+}
+
+// List is the envelope of structured output, the same whether one or many
+// objects are written.
+type List struct {
+	APIVersion string        `json:"apiVersion"`
+	Kind       string        `json:"kind"`
+	Items      []interface{} `json:"items"`
+}
+
+// WriteOutput writes items, a slice of API objects of the given kind, as
+// the structured output o.
+func WriteOutput(out io.Writer, o Output, kind string, items interface{}) error {
+	list := List{
+		APIVersion: OutputAPIVersion,
+		Kind:       kind + "List",
+		Items:      []interface{}{},
+	}
+	v := reflect.ValueOf(items)
+	for i := 0; i < v.Len(); i++ {
+		list.Items = append(list.Items, outputItem(v.Index(i).Interface()))
+	}
+
+	switch o.Key {
+	case JSONOutputKey:
+		data, err := json.MarshalIndent(list, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "%s\n", data)
+		return err
+	case YAMLOutputKey:
+		doc, err := decodeJSON(list)
+		if err != nil {
+			return err
+		}
+		data, err := yaml.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	case JSONPathOutputKey:
+		doc, err := decodeJSON(list)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := o.JSONPath.Execute(&buf, doc); err != nil {
+			return err
+		}
+		if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		_, err = buf.WriteTo(out)
+		return err
+	case NameOutputKey:
+		for _, item := range list.Items {
+			name, ok := itemName(item)
+			if !ok {
+				return fmt.Errorf("output name is not supported for %s objects", kind)
+			}
+			if _, err := fmt.Fprintf(out, "%s/%s\n", strings.ToLower(kind), name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("output %q is not structured", o.Key)
+}
+
+// decodeJSON returns v as decoded JSON, so that other encodings use the
+// same field names.
+func decodeJSON(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	err = dec.Decode(&doc)
+	return doc, err
+}
+
+// policyItem is a policy with its ID, which PolicyWithID leaves out of its
+// JSON.
+type policyItem struct {
+	ID string `json:"id"`
+	types.Policy
+}
+
+// outputItem returns the object written for item.
+func outputItem(item interface{}) interface{} {
+	if p, ok := item.(*types.PolicyWithID); ok {
+		return &policyItem{ID: p.ID, Policy: p.Policy}
+	}
+	return item
+}
+
+// itemName returns the name written by -o name, qualified by the
+// namespace for namespaced objects.
+func itemName(item interface{}) (string, bool) {
+	switch v := item.(type) {
+	case *types.Volume:
+		return v.Namespace + "/" + v.Name, true
+	case *types.Rule:
+		return v.Namespace + "/" + v.Name, true
+	case *types.Controller:
+		return v.Name, true
+	case *types.Pool:
+		return v.Name, true
+	case *types.Namespace:
+		return v.Name, true
+	case *types.User:
+		return v.Username, true
+	case *policyItem:
+		return v.ID, true
+	case *cliTypes.Node:
+		return v.Name, true
+	case Capacity:
+		return v.Scope + "/" + v.Name, true
+	}
+	return "", false
+}
@@ -0,0 +1,72 @@
+package formatter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+func TestParseOutput(t *testing.T) {
+	o, err := ParseOutput("", "", true)
+	assert.NilError(t, err)
+	assert.Equal(t, o.IsStructured(), false)
+
+	o, err = ParseOutput("wide", "", false)
+	assert.NilError(t, err)
+	assert.Equal(t, o.IsWide(), true)
+	assert.Equal(t, o.IsStructured(), false)
+
+	o, err = ParseOutput("jsonpath={.items[*].name}", "", false)
+	assert.NilError(t, err)
+	assert.Equal(t, o.IsStructured(), true)
+
+	for _, tt := range []struct {
+		output, format string
+		quiet          bool
+		err            string
+	}{
+		{output: "json", format: "table", err: "Conflicting options"},
+		{output: "json", quiet: true, err: "Conflicting options"},
+		{output: "xml", err: "unknown output"},
+		{output: "json=x", err: "takes no template"},
+		{output: "jsonpath", err: "requires a template"},
+		{output: "jsonpath={.items", err: "invalid jsonpath template"},
+	} {
+		_, err := ParseOutput(tt.output, tt.format, tt.quiet)
+		assert.Error(t, err, tt.err)
+	}
+}
+
+func TestWriteOutput(t *testing.T) {
+	volumes := []*types.Volume{
+		{ID: "1", Name: "a", Namespace: "default", Size: 5},
+		{ID: "2", Name: "b", Namespace: "test", Size: 10},
+	}
+
+	write := func(output string, items interface{}) string {
+		o, err := ParseOutput(output, "", false)
+		assert.NilError(t, err)
+		var buf bytes.Buffer
+		assert.NilError(t, WriteOutput(&buf, o, VolumeKind, items))
+		return buf.String()
+	}
+
+	assert.Equal(t, write("jsonpath={.kind} {.items[*].name}", volumes), "VolumeList a b\n")
+	assert.Equal(t, write("name", volumes), "volume/default/a\nvolume/test/b\n")
+	assert.Contains(t, write("json", volumes), `"apiVersion": "v1"`)
+	assert.Contains(t, write("yaml", volumes), "kind: VolumeList\n")
+	assert.Contains(t, write("yaml", volumes), "  size: 10\n")
+
+	// Empty lists still have their envelope.
+	assert.Contains(t, write("json", []*types.Volume{}), `"items": []`)
+	assert.Contains(t, write("yaml", []*types.Volume(nil)), "items: []\n")
+
+	// Policies keep their ID.
+	policies := []*types.PolicyWithID{{ID: "p1", Policy: types.Policy{}}}
+	o, _ := ParseOutput("jsonpath={.items[0].id}", "", false)
+	var buf bytes.Buffer
+	assert.NilError(t, WriteOutput(&buf, o, PolicyKind, policies))
+	assert.Equal(t, buf.String(), "p1\n")
+}
@@ -6,6 +6,7 @@ import (
 
 const (
 	defaultPolicyTableFormat = "table {{.ID}}\t{{.User}}\t{{.Group}}\t{{.Namespace}}"
+	widePolicyTableFormat    = "table {{.ID}}\t{{.User}}\t{{.Group}}\t{{.Namespace}}\t{{.Readonly}}\t{{.APIGroup}}\t{{.Resource}}\t{{.NonResourcePath}}"
 
 	policyIDHeader              = "ID"
 	policyUserHeader            = "User"
@@ -21,6 +22,8 @@ func NewPolicyFormat(source string) Format {
 	switch source {
 	case TableFormatKey:
 		return defaultPolicyTableFormat
+	case WideFormatKey:
+		return widePolicyTableFormat
 	case RawFormatKey:
 		return "id: {{.ID}}\nuser: {{.User}}\ngroup: {{.Group}}\nnamespace: {{.Namespace}}"
 	}
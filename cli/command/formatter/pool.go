@@ -12,6 +12,7 @@ import (
 const (
 	defaultPoolQuietFormat = "{{.Name}}"
 	defaultPoolTableFormat = "table {{.Name}}\t{{.Drivers}}\t{{.Nodes}}\t{{.Total}}\t{{.CapacityUsed}}\t{{.Active}}"
+	widePoolTableFormat    = "table {{.Name}}\t{{.Drivers}}\t{{.Nodes}}\t{{.Total}}\t{{.CapacityUsed}}\t{{.Active}}\t{{.Labels}}"
 
 	poolNameHeader         = "NAME"
 	poolDriversHeader      = "DRIVERS"
@@ -29,6 +30,8 @@ func NewPoolFormat(source string, quiet bool) Format {
 			return defaultPoolQuietFormat
 		}
 		return defaultPoolTableFormat
+	case WideFormatKey:
+		return widePoolTableFormat
 	case RawFormatKey:
 		if quiet {
 			return `name: {{.Name}}`
@@ -10,6 +10,7 @@ import (
 const (
 	defaultRuleQuietFormat = "{{.Name}}"
 	defaultRuleTableFormat = "table {{.Name}}\t{{.Selector}}\t{{.RuleAction}}\t{{.Labels}}"
+	wideRuleTableFormat    = "table {{.Name}}\t{{.Selector}}\t{{.RuleAction}}\t{{.Labels}}\t{{.Active}}"
 
 	ruleNameHeader     = "NAMESPACE/NAME"
 	ruleSelectorHeader = "SELECTOR"
@@ -27,6 +28,8 @@ func NewRuleFormat(source string, quiet bool) Format {
 			return defaultRuleQuietFormat
 		}
 		return defaultRuleTableFormat
+	case WideFormatKey:
+		return wideRuleTableFormat
 	case RawFormatKey:
 		if quiet {
 			return `name: {{.Name}}`
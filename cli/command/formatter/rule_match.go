@@ -0,0 +1,91 @@
+package formatter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/storageos/go-api/types"
+)
+
+const (
+	defaultRuleMatchQuietFormat = "{{.Name}}"
+	defaultRuleMatchTableFormat = "table {{.Name}}\t{{.Before}}\t{{.After}}"
+
+	ruleMatchNameHeader   = "VOLUME"
+	ruleMatchBeforeHeader = "BEFORE"
+	ruleMatchAfterHeader  = "AFTER"
+)
+
+// RuleMatch is a volume matched by a rule, with the labels it has and the
+// labels it would have once the rules of its namespace are applied.
+type RuleMatch struct {
+	Volume *types.Volume
+	Before map[string]string
+	After  map[string]string
+}
+
+// NewRuleMatchFormat returns a format for use with a rule match Context
+func NewRuleMatchFormat(source string, quiet bool) Format {
+	switch source {
+	case TableFormatKey:
+		if quiet {
+			return defaultRuleMatchQuietFormat
+		}
+		return defaultRuleMatchTableFormat
+	case RawFormatKey:
+		if quiet {
+			return `name: {{.Name}}`
+		}
+		return `name: {{.Name}}\nbefore: {{.Before}}\nafter: {{.After}}\n`
+	}
+	return Format(source)
+}
+
+// RuleMatchWrite writes formatted rule matches using the Context
+func RuleMatchWrite(ctx Context, matches []RuleMatch) error {
+	render := func(format func(subContext subContext) error) error {
+		for _, m := range matches {
+			if err := format(&ruleMatchContext{v: m}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return ctx.Write(&ruleMatchContext{}, render)
+}
+
+type ruleMatchContext struct {
+	HeaderContext
+	v RuleMatch
+}
+
+func (c *ruleMatchContext) MarshalJSON() ([]byte, error) {
+	return marshalJSON(c)
+}
+
+func (c *ruleMatchContext) Name() string {
+	c.AddHeader(ruleMatchNameHeader)
+	return fmt.Sprintf("%s/%s", c.v.Volume.Namespace, c.v.Volume.Name)
+}
+
+func (c *ruleMatchContext) Before() string {
+	c.AddHeader(ruleMatchBeforeHeader)
+	return sortedLabels(c.v.Before)
+}
+
+func (c *ruleMatchContext) After() string {
+	c.AddHeader(ruleMatchAfterHeader)
+	return sortedLabels(c.v.After)
+}
+
+// sortedLabels joins labels as key=value pairs, sorted by key so that the
+// before and after labels line up.
+func sortedLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,120 @@
+package formatter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/storageos/go-api/types"
+)
+
+const (
+	defaultTemplateQuietFormat = "{{.Name}}"
+	defaultTemplateTableFormat = "table {{.Name}}\t{{.Format}}\t{{.ObjectTypes}}\t{{.Weight}}\t{{.Active}}"
+
+	templateNameHeader        = "NAME"
+	templateDescriptionHeader = "DESCRIPTION"
+	templateFormatHeader      = "TEMPLATE"
+	templateObjectTypesHeader = "OBJECTS"
+	templateWeightHeader      = "WEIGHT"
+	templateActiveHeader      = "STATUS"
+)
+
+// NewTemplateFormat returns a format for use with a template Context
+func NewTemplateFormat(source string, quiet bool) Format {
+	switch source {
+	case TableFormatKey:
+		if quiet {
+			return defaultTemplateQuietFormat
+		}
+		return defaultTemplateTableFormat
+	case RawFormatKey:
+		if quiet {
+			return `name: {{.Name}}`
+		}
+		return `name: {{.Name}}\ntemplate: {{.Format}}\nobjects: {{.ObjectTypes}}\n`
+	}
+	return Format(source)
+}
+
+// TemplateWrite writes formatted templates using the Context
+func TemplateWrite(ctx Context, templates []*types.Template) error {
+	render := func(format func(subContext subContext) error) error {
+		for _, template := range templates {
+			if err := format(&templateContext{v: *template}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return ctx.Write(&templateContext{}, render)
+}
+
+type templateContext struct {
+	HeaderContext
+	v types.Template
+}
+
+func (c *templateContext) MarshalJSON() ([]byte, error) {
+	return marshalJSON(c)
+}
+
+func (c *templateContext) Name() string {
+	c.AddHeader(templateNameHeader)
+	return c.v.Name
+}
+
+func (c *templateContext) Description() string {
+	c.AddHeader(templateDescriptionHeader)
+	return c.v.Description
+}
+
+func (c *templateContext) Format() string {
+	c.AddHeader(templateFormatHeader)
+	return c.v.Format
+}
+
+func (c *templateContext) ObjectTypes() string {
+	c.AddHeader(templateObjectTypesHeader)
+	return strings.Join(c.v.ObjectTypes, ", ")
+}
+
+func (c *templateContext) Weight() string {
+	c.AddHeader(templateWeightHeader)
+	return strconv.Itoa(c.v.Weight)
+}
+
+func (c *templateContext) Active() string {
+	c.AddHeader(templateActiveHeader)
+	if c.v.Active {
+		return "active"
+	}
+	return "disabled"
+}
+
+func (c *templateContext) Labels() string {
+	c.AddHeader(labelsHeader)
+	if c.v.Labels == nil {
+		return ""
+	}
+
+	var joinLabels []string
+	for k, v := range c.v.Labels {
+		joinLabels = append(joinLabels, fmt.Sprintf("%s=%s", k, v))
+	}
+	return strings.Join(joinLabels, ",")
+}
+
+func (c *templateContext) Label(name string) string {
+
+	n := strings.Split(name, ".")
+	r := strings.NewReplacer("-", " ", "_", " ")
+	h := r.Replace(n[len(n)-1])
+
+	c.AddHeader(h)
+
+	if c.v.Labels == nil {
+		return ""
+	}
+	return c.v.Labels[name]
+}
@@ -17,7 +17,7 @@ const (
 
 func NewUserFormat(source string, quiet bool) Format {
 	switch source {
-	case TableFormatKey:
+	case TableFormatKey, WideFormatKey:
 		if quiet {
 			return defaultUserQuietFormat
 		}
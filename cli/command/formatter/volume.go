@@ -13,7 +13,9 @@ import (
 const (
 	defaultVolumeQuietFormat = "{{.Name}}"
 	defaultVolumeTableFormat = "table {{.Name}}\t{{.Size}}\t{{.MountedBy}}\t{{.NodeSelector}}\t{{.Status}}\t{{.Replicas}}\t{{.Location}}"
+	wideVolumeTableFormat    = "table {{.ID}}\t{{.Name}}\t{{.Size}}\t{{.MountedBy}}\t{{.NodeSelector}}\t{{.Status}}\t{{.Replicas}}\t{{.Location}}\t{{.Labels}}"
 
+	volumeIDHeader           = "ID"
 	volumeNameHeader         = "NAMESPACE/NAME"
 	volumeMountedByHeader    = "MOUNTED BY"
 	volumeNodeSelectorHeader = "NODE SELECTOR"
@@ -30,6 +32,8 @@ func NewVolumeFormat(source string, quiet bool) Format {
 			return defaultVolumeQuietFormat
 		}
 		return defaultVolumeTableFormat
+	case WideFormatKey:
+		return wideVolumeTableFormat
 	case RawFormatKey:
 		if quiet {
 			return `name: {{.Name}}`
@@ -62,6 +66,11 @@ func (c *volumeContext) MarshalJSON() ([]byte, error) {
 	return marshalJSON(c)
 }
 
+func (c *volumeContext) ID() string {
+	c.AddHeader(volumeIDHeader)
+	return c.v.ID
+}
+
 func (c *volumeContext) Name() string {
 	c.AddHeader(volumeNameHeader)
 	return fmt.Sprintf("%s/%s", c.v.Namespace, c.v.Name)
@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 	"text/template"
 
 	"github.com/sirupsen/logrus"
 	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command/formatter"
 	"github.com/storageos/go-cli/pkg/templates"
 )
 
@@ -60,17 +62,16 @@ func Inspect(out io.Writer, references []string, tmplStr string, getRef GetRefFu
 		return cli.StatusError{StatusCode: 64, Status: err.Error()}
 	}
 
-	var inspectErr error
+	var inspectErrs []string
 	for _, ref := range references {
 		element, raw, err := getRef(ref)
 		if err != nil {
-			inspectErr = err
-			break
+			inspectErrs = append(inspectErrs, err.Error())
+			continue
 		}
 
 		if err := inspector.Inspect(element, raw); err != nil {
-			inspectErr = err
-			break
+			inspectErrs = append(inspectErrs, err.Error())
 		}
 	}
 
@@ -78,10 +79,47 @@ func Inspect(out io.Writer, references []string, tmplStr string, getRef GetRefFu
 		logrus.Errorf("%s\n", err)
 	}
 
-	if inspectErr != nil {
-		return cli.StatusError{StatusCode: 1, Status: inspectErr.Error()}
+	return refErrors(inspectErrs)
+}
+
+// Output fetches objects by reference using GetRefFunc and writes them as
+// the structured output selected with -o, in a list of the given kind.
+func Output(out io.Writer, references []string, output string, tmplStr string, kind string, getRef GetRefFunc) error {
+	o, err := formatter.ParseOutput(output, tmplStr, false)
+	if err != nil {
+		return cli.StatusError{StatusCode: 64, Status: err.Error()}
 	}
-	return nil
+	if !o.IsStructured() {
+		return cli.StatusError{StatusCode: 64, Status: fmt.Sprintf("output %s is only supported when listing", o.Key)}
+	}
+
+	var (
+		elements    []interface{}
+		inspectErrs []string
+	)
+	for _, ref := range references {
+		element, _, err := getRef(ref)
+		if err != nil {
+			inspectErrs = append(inspectErrs, err.Error())
+			continue
+		}
+		elements = append(elements, element)
+	}
+
+	if err := formatter.WriteOutput(out, o, kind, elements); err != nil {
+		return err
+	}
+
+	return refErrors(inspectErrs)
+}
+
+// refErrors returns the errors met inspecting references as a single
+// StatusError, one per line, or nil if there are none.
+func refErrors(errs []string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return cli.StatusError{StatusCode: 1, Status: strings.Join(errs, "\n")}
 }
 
 // Inspect executes the inspect template.
@@ -0,0 +1,39 @@
+package inspect
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+func getNames(ref string) (interface{}, []byte, error) {
+	if ref == "missing" || ref == "gone" {
+		return nil, nil, fmt.Errorf("no such object: %s", ref)
+	}
+	return map[string]string{"name": ref}, nil, nil
+}
+
+func TestOutputReportsEachFailedRef(t *testing.T) {
+	var buf bytes.Buffer
+	err := Output(&buf, []string{"missing", "a", "gone", "b"}, "json", "", "test", getNames)
+
+	status, ok := err.(cli.StatusError)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, status.StatusCode, 1)
+	assert.Equal(t, status.Status, "no such object: missing\nno such object: gone")
+	assert.Contains(t, buf.String(), `"name": "a"`)
+	assert.Contains(t, buf.String(), `"name": "b"`)
+}
+
+func TestInspectReportsEachFailedRef(t *testing.T) {
+	var buf bytes.Buffer
+	err := Inspect(&buf, []string{"a", "missing", "b"}, "{{.name}}", getNames)
+
+	status, ok := err.(cli.StatusError)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, status.Status, "no such object: missing")
+	assert.Equal(t, buf.String(), "a\nb\n")
+}
@@ -1,9 +1,11 @@
 package login
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"syscall"
+	"time"
 
 	"github.com/dnephin/cobra"
 
@@ -54,23 +56,25 @@ func NewLoginCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 	return cmd
 }
 
-func verifyCredsWithServer(username, password, host string) error {
+// verifyCredsWithServer logs in to host, returning the session token it
+// hands out and its expiry.
+func verifyCredsWithServer(username, password, host string, tlsConfig *tls.Config) (string, time.Time, error) {
 	h, err := opts.ParseHost(true, host)
 	if err != nil {
-		return fmt.Errorf("Failed to verify credentials (%v)", err)
+		return "", time.Time{}, fmt.Errorf("Failed to verify credentials (%v)", err)
 	}
 
-	client, err := api.NewVersionedClient(h, api.DefaultVersionStr)
+	client, err := command.NewVersionedClient(h, api.DefaultVersionStr, tlsConfig)
 	if err != nil {
-		return fmt.Errorf("Failed to verify credentials (%v)", err)
+		return "", time.Time{}, fmt.Errorf("Failed to verify credentials (%v)", err)
 	}
 	client.SetAuth(username, password)
 
-	_, err = client.Login()
+	token, expires, err := command.NewSessionToken(client)
 	if err != nil {
-		return fmt.Errorf("Failed to verify credentials (%v)", err)
+		return "", time.Time{}, fmt.Errorf("Failed to verify credentials (%v)", err)
 	}
-	return nil
+	return token, expires, nil
 }
 
 func getHost(opt loginOptions, args []string) (string, error) {
@@ -131,13 +135,19 @@ func runLogin(storageosCli *command.StorageOSCli, opt loginOptions, args []strin
 		}
 	}
 
-	if verr := verifyCredsWithServer(opt.username, opt.password, opt.host); verr != nil {
-		return verr
+	token, expires, err := verifyCredsWithServer(opt.username, opt.password, opt.host, storageosCli.Client().TLSConfig)
+	if err != nil {
+		return err
 	}
 
 	fmt.Fprintln(storageosCli.Out(), "Credentials verified")
 
-	err = storageosCli.ConfigFile().CredentialsStore.SetCredentials(opt.host, opt.username, opt.password)
+	// The password is only kept to get a new token once it expires.
+	err = storageosCli.ConfigFile().SetCredentials(opt.host, opt.username, opt.password)
+	if err != nil {
+		return err
+	}
+	err = storageosCli.ConfigFile().CredentialsStore.SetToken(opt.host, token, expires)
 	if err != nil {
 		return err
 	}
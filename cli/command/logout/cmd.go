@@ -19,7 +19,7 @@ func NewLogoutCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "logout [HOST]",
-		Short: "Delete stored login credentials for a given storageos host",
+		Short: "Delete stored login credentials and session token for a given storageos host",
 		Args:  cli.RequiresMaxArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runDelete(storageosCli, opt, args)
@@ -62,7 +62,11 @@ func runDelete(storageosCli *command.StorageOSCli, opt logoutOptions, args []str
 
 	conf := storageosCli.ConfigFile()
 
-	conf.CredentialsStore.DeleteCredentials(host)
-	return conf.Save()
+	// Forget the host even if its password couldn't be erased.
+	err = conf.CredentialsStore.DeleteCredentials(host)
+	if saveErr := conf.Save(); saveErr != nil {
+		return saveErr
+	}
+	return err
 
 }
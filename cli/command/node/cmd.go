@@ -21,7 +21,9 @@ func NewNodeCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 		command.WithAlias(newHealthCommand(storageosCli), command.HealthAliases...),
 		newCordonCommand(storageosCli),
 		newUncordonCommand(storageosCli),
+		newDrainCommand(storageosCli),
 		command.WithAlias(newUpdateCommand(storageosCli), command.UpdateAliases...),
+		command.WithAlias(newRemoveCommand(storageosCli), command.RemoveAliases...),
 	)
 	return cmd
 }
@@ -0,0 +1,379 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dnephin/cobra"
+	api "github.com/storageos/go-api"
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+	cliconfig "github.com/storageos/go-cli/cli/config"
+)
+
+const drainPollInterval = 2 * time.Second
+
+var errDrainTimeout = errors.New("timed out waiting for the node to drain, run the command again to continue")
+
+type drainOptions struct {
+	node    string
+	timeout time.Duration
+	dryRun  bool
+}
+
+func newDrainCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	var opt drainOptions
+
+	cmd := &cobra.Command{
+		Use:     "drain [OPTIONS] NODE",
+		Short:   "Cordon a node and move all volumes off it",
+		Long:    drainDescription,
+		Example: drainExample,
+		Args:    cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opt.node = args[0]
+			return runDrain(storageosCli, opt)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.DurationVarP(&opt.timeout, "timeout", "t", 10*time.Minute, "Time to wait for the node to be drained")
+	flags.BoolVar(&opt.dryRun, "dry-run", false, "Only list the volumes that would be moved")
+	return cmd
+}
+
+// drainMove is the planned relocation of a deployment off the drained node.
+type drainMove struct {
+	volumeDeployment
+	// target is the node a master is moved to, empty for replicas.
+	target *types.Controller
+}
+
+func (m drainMove) action() string {
+	if m.master {
+		return "move master to " + m.target.Name
+	}
+	return "re-create replica"
+}
+
+func runDrain(storageosCli *command.StorageOSCli, opt drainOptions) error {
+	client := storageosCli.Client()
+	out := storageosCli.Out()
+
+	node, err := client.Controller(opt.node)
+	if err != nil {
+		return err
+	}
+	nodes, err := client.ControllerList(types.ListOptions{})
+	if err != nil {
+		return err
+	}
+	volumes, err := client.VolumeList(types.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	moves, err := planDrain(node, nodes, nodeDeployments(node, volumes))
+	if err != nil {
+		return err
+	}
+
+	if opt.dryRun {
+		if !node.Cordon {
+			fmt.Fprintf(out, "node %s would be cordoned\n", node.Name)
+		}
+		if len(moves) == 0 {
+			fmt.Fprintf(out, "no volumes to move off %s\n", node.Name)
+			return nil
+		}
+		w := tabwriter.NewWriter(out, 20, 1, 3, ' ', 0)
+		fmt.Fprintln(w, "VOLUME\tROLE\tACTION")
+		for _, m := range moves {
+			role := "replica"
+			if m.master {
+				role = "master"
+			}
+			fmt.Fprintf(w, "%s/%s\t%s\t%s\n", m.volume.Namespace, m.volume.Name, role, m.action())
+		}
+		return w.Flush()
+	}
+
+	if !node.Cordon {
+		if _, err := client.ControllerUpdate(types.ControllerUpdateOptions{
+			ID:          node.ID,
+			Name:        node.Name,
+			Description: node.Description,
+			Labels:      node.Labels,
+			Cordon:      true,
+		}); err != nil {
+			return fmt.Errorf("Failed to cordon node (%s): %v", node.Name, err)
+		}
+		fmt.Fprintf(out, "node %s cordoned\n", node.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opt.timeout)
+	defer cancel()
+
+	for _, m := range moves {
+		fmt.Fprintf(out, "%s/%s: %s\n", m.volume.Namespace, m.volume.Name, m.action())
+
+		if m.master {
+			err = moveMaster(ctx, client, m.volume, m.target)
+		} else {
+			err = relocateReplica(ctx, client, m.volume, node)
+		}
+		if err != nil {
+			if err == context.DeadlineExceeded {
+				return errDrainTimeout
+			}
+			return err
+		}
+	}
+
+	last := types.VolumeStats{MasterVolumeCount: -1}
+	err = command.Poll(ctx, drainPollInterval, func() (bool, error) {
+		n, err := client.Controller(node.ID)
+		if err != nil {
+			return false, err
+		}
+		stats := n.VolumeStats
+		if stats != last {
+			fmt.Fprintf(out, "%s: %d master and %d replica volume(s) remaining\n", node.Name, stats.MasterVolumeCount, stats.ReplicaVolumeCount)
+			last = stats
+		}
+		return !hasVolumes(n), nil
+	})
+	if err == context.DeadlineExceeded {
+		return errDrainTimeout
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "node %s drained\n", node.Name)
+	return nil
+}
+
+// planDrain decides where each deployment on node goes. Masters are spread
+// over the schedulable nodes holding the fewest masters, replicas are left
+// to the scheduler, which no longer places anything on the cordoned node.
+func planDrain(node *types.Controller, nodes []*types.Controller, deployments []volumeDeployment) ([]drainMove, error) {
+	var candidates []*types.Controller
+	masters := make(map[string]int)
+	for _, n := range nodes {
+		if n.ID == node.ID || n.Cordon || (n.Health != "" && n.Health != types.ControllerHealthOK) {
+			continue
+		}
+		candidates = append(candidates, n)
+		masters[n.ID] = n.VolumeStats.MasterVolumeCount
+	}
+
+	moves := make([]drainMove, 0, len(deployments))
+	for _, d := range deployments {
+		m := drainMove{volumeDeployment: d}
+		if d.master {
+			// Don't move a master onto a node that already holds one of its
+			// replicas.
+			var target *types.Controller
+			for _, c := range candidates {
+				if hasReplicaOn(d.volume, c) {
+					continue
+				}
+				if target == nil || masters[c.ID] < masters[target.ID] ||
+					(masters[c.ID] == masters[target.ID] && c.Name < target.Name) {
+					target = c
+				}
+			}
+			if target == nil {
+				return nil, fmt.Errorf("no schedulable node available to move the master of %s/%s to", d.volume.Namespace, d.volume.Name)
+			}
+			masters[target.ID]++
+			m.target = target
+		}
+		moves = append(moves, m)
+	}
+
+	// Move masters first so the volumes stay available while replicas are
+	// being re-created.
+	sort.SliceStable(moves, func(i, j int) bool {
+		return moves[i].master && !moves[j].master
+	})
+	return moves, nil
+}
+
+// moveMaster moves the master of vol to target by pointing the node selector
+// of the volume at target until the master is there, and then restoring the
+// original node selector so that the volume isn't left pinned to target.
+func moveMaster(ctx context.Context, client *api.Client, vol *types.Volume, target *types.Controller) error {
+	original := vol.NodeSelector
+
+	setSelector := func(ctx context.Context, selector string) error {
+		current, err := client.Volume(vol.Namespace, vol.Name)
+		if err != nil {
+			return err
+		}
+		current.NodeSelector = selector
+		_, err = client.VolumeUpdate(volumeUpdateOptions(ctx, current))
+		return err
+	}
+
+	if err := setSelector(ctx, target.Name); err != nil {
+		return err
+	}
+	err := command.Poll(ctx, drainPollInterval, func() (bool, error) {
+		current, err := client.Volume(vol.Namespace, vol.Name)
+		if err != nil {
+			return false, err
+		}
+		for _, d := range nodeDeployments(target, []*types.Volume{current}) {
+			if d.master {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+
+	// Restore the node selector even if the master didn't move in time, with
+	// a context of its own as ctx may have expired.
+	restoreCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if restoreErr := setSelector(restoreCtx, original); restoreErr != nil && err == nil {
+		err = fmt.Errorf("failed to restore the node selector of %s/%s: %v", vol.Namespace, vol.Name, restoreErr)
+	}
+	return err
+}
+
+// relocateReplica moves the replicas of vol off node by lowering the desired
+// replica count and raising it again, until none of them is placed on node.
+// The replica count is written explicitly while doing so, and the original
+// label is restored at the end, whether the replicas moved or not.
+func relocateReplica(ctx context.Context, client *api.Client, vol *types.Volume, node *types.Controller) (err error) {
+	original, hadLabel := vol.Labels[cliconfig.FeatureReplicas]
+	desired, convErr := strconv.Atoi(original)
+	if convErr != nil || desired < len(vol.Replicas) {
+		desired = len(vol.Replicas)
+	}
+	if desired == 0 {
+		return nil
+	}
+
+	// setReplicas sets the replica count label, or removes it if value is
+	// empty.
+	setReplicas := func(ctx context.Context, value string) error {
+		current, err := client.Volume(vol.Namespace, vol.Name)
+		if err != nil {
+			return err
+		}
+		if current.Labels == nil {
+			current.Labels = make(map[string]string)
+		}
+		if value == "" {
+			delete(current.Labels, cliconfig.FeatureReplicas)
+		} else {
+			current.Labels[cliconfig.FeatureReplicas] = value
+		}
+		_, err = client.VolumeUpdate(volumeUpdateOptions(ctx, current))
+		return err
+	}
+
+	waitReplicas := func(count int) error {
+		return command.Poll(ctx, drainPollInterval, func() (bool, error) {
+			current, err := client.Volume(vol.Namespace, vol.Name)
+			if err != nil {
+				return false, err
+			}
+			return len(current.Replicas) == count, nil
+		})
+	}
+
+	changed := false
+	defer func() {
+		if !changed {
+			return
+		}
+		// Restore the label even if the replicas didn't move in time, with
+		// a context of its own as ctx may have expired.
+		restore := ""
+		if hadLabel {
+			restore = original
+		}
+		restoreCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if restoreErr := setReplicas(restoreCtx, restore); restoreErr != nil && err == nil {
+			err = fmt.Errorf("failed to restore the replica count of %s/%s: %v", vol.Namespace, vol.Name, restoreErr)
+		}
+	}()
+
+	// The scheduler picks which replica goes, so it may take a few rounds
+	// before the one on node is dropped.
+	for attempt := 0; attempt <= desired; attempt++ {
+		current, err := client.Volume(vol.Namespace, vol.Name)
+		if err != nil {
+			return err
+		}
+		if !hasReplicaOn(current, node) {
+			return nil
+		}
+
+		changed = true
+		if err := setReplicas(ctx, strconv.Itoa(desired-1)); err != nil {
+			return err
+		}
+		if err := waitReplicas(desired - 1); err != nil {
+			return err
+		}
+		if err := setReplicas(ctx, strconv.Itoa(desired)); err != nil {
+			return err
+		}
+		if err := waitReplicas(desired); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("could not move the replica of %s/%s off %s", vol.Namespace, vol.Name, node.Name)
+}
+
+func hasReplicaOn(vol *types.Volume, node *types.Controller) bool {
+	for _, d := range nodeDeployments(node, []*types.Volume{vol}) {
+		if !d.master {
+			return true
+		}
+	}
+	return false
+}
+
+func volumeUpdateOptions(ctx context.Context, vol *types.Volume) types.VolumeUpdateOptions {
+	return types.VolumeUpdateOptions{
+		ID:           vol.ID,
+		Name:         vol.Name,
+		Namespace:    vol.Namespace,
+		Description:  vol.Description,
+		Size:         vol.Size,
+		NodeSelector: vol.NodeSelector,
+		Labels:       vol.Labels,
+		Context:      ctx,
+	}
+}
+
+var drainDescription = `
+Prepare a node for maintenance. The node is cordoned so that nothing new is
+scheduled on it, master volumes are moved to other nodes by pointing their
+node selector at the new node until the master has moved, and replicas are
+re-created elsewhere by lowering and raising the replica count. The original
+node selectors and replica counts are restored afterwards. The command
+returns once the node no longer holds any volumes, or fails after --timeout.
+`
+
+var drainExample = `
+$ storageos node drain --dry-run storageos-1
+VOLUME                 ROLE      ACTION
+default/db01           master    move master to storageos-2
+default/db02           replica   re-create replica
+
+$ storageos node drain --timeout 30m storageos-1
+`
@@ -0,0 +1,171 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	api "github.com/storageos/go-api"
+	"github.com/storageos/go-api/types"
+)
+
+func TestPlanDrain(t *testing.T) {
+	drained := &types.Controller{ID: "n1", Name: "storageos-1"}
+	nodes := []*types.Controller{
+		drained,
+		{ID: "n2", Name: "storageos-2", Health: "healthy", VolumeStats: types.VolumeStats{MasterVolumeCount: 3}},
+		{ID: "n3", Name: "storageos-3", Health: "healthy", VolumeStats: types.VolumeStats{MasterVolumeCount: 1}},
+		{ID: "n4", Name: "storageos-4", Health: "healthy", Cordon: true},
+		{ID: "n5", Name: "storageos-5", Health: "offline"},
+	}
+
+	db01 := &types.Volume{Name: "db01", Namespace: "default", Master: &types.Deployment{Controller: "n1"}}
+	db02 := &types.Volume{Name: "db02", Namespace: "default", Master: &types.Deployment{Controller: "n1"},
+		Replicas: []*types.Deployment{{Controller: "n3"}}}
+	db03 := &types.Volume{Name: "db03", Namespace: "default", Master: &types.Deployment{Controller: "n2"},
+		Replicas: []*types.Deployment{{Controller: "n1"}}}
+
+	moves, err := planDrain(drained, nodes, nodeDeployments(drained, []*types.Volume{db03, db01, db02}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(moves) != 3 {
+		t.Fatalf("expected 3 moves, got %d", len(moves))
+	}
+
+	expected := []struct {
+		volume string
+		action string
+	}{
+		// least loaded node first
+		{"db01", "move master to storageos-3"},
+		// storageos-3 holds a replica of db02
+		{"db02", "move master to storageos-2"},
+		// replicas are moved after masters
+		{"db03", "re-create replica"},
+	}
+	for i, e := range expected {
+		if moves[i].volume.Name != e.volume || moves[i].action() != e.action {
+			t.Errorf("move %d: expected %s: %s, got %s: %s", i, e.volume, e.action, moves[i].volume.Name, moves[i].action())
+		}
+	}
+}
+
+func TestPlanDrainNoTarget(t *testing.T) {
+	drained := &types.Controller{ID: "n1", Name: "storageos-1"}
+	nodes := []*types.Controller{drained, {ID: "n2", Name: "storageos-2", Cordon: true}}
+	vol := &types.Volume{Name: "db01", Namespace: "default", Master: &types.Deployment{Controller: "n1"}}
+
+	if _, err := planDrain(drained, nodes, nodeDeployments(drained, []*types.Volume{vol})); err == nil {
+		t.Fatal("expected an error when no node can take the master")
+	}
+}
+
+func TestMoveMasterRestoresNodeSelector(t *testing.T) {
+	target := &types.Controller{ID: "n2", Name: "storageos-2"}
+	vol := &types.Volume{ID: "v1", Name: "db01", Namespace: "default", NodeSelector: "ssd",
+		Master: &types.Deployment{Controller: "n1"}}
+
+	// The fake scheduler moves the master as soon as the volume is pinned
+	// to the target.
+	var selectors []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/version":
+			w.Write([]byte(`{"apiVersion": "1"}`))
+			return
+		case r.URL.Path != "/v1/namespaces/default/volumes/db01":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case r.Method == http.MethodPut:
+			var opts types.VolumeUpdateOptions
+			if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+				t.Error(err)
+				return
+			}
+			selectors = append(selectors, opts.NodeSelector)
+			vol.NodeSelector = opts.NodeSelector
+			if opts.NodeSelector == target.Name {
+				vol.Master = &types.Deployment{Controller: target.ID}
+			}
+		}
+		json.NewEncoder(w).Encode(vol)
+	}))
+	defer srv.Close()
+
+	client, err := api.NewVersionedClient(srv.URL, api.DefaultVersionStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := moveMaster(context.Background(), client, vol, target); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(selectors) != 2 || selectors[0] != "storageos-2" || selectors[1] != "ssd" {
+		t.Errorf("expected the node selector to be set to storageos-2 and restored to ssd, got %q", selectors)
+	}
+	if vol.Master.Controller != "n2" {
+		t.Errorf("expected the master on n2, got %s", vol.Master.Controller)
+	}
+}
+
+func TestRelocateReplicaRestoresReplicasOnFailure(t *testing.T) {
+	node := &types.Controller{ID: "n1", Name: "storageos-1"}
+	vol := &types.Volume{ID: "v1", Name: "db02", Namespace: "default",
+		Master:   &types.Deployment{Controller: "n2"},
+		Replicas: []*types.Deployment{{Controller: "n1"}}}
+
+	// The fake scheduler never drops the replica, so waiting for it times
+	// out.
+	var (
+		mu     sync.Mutex
+		counts []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.URL.Path == "/version":
+			w.Write([]byte(`{"apiVersion": "1"}`))
+			return
+		case r.URL.Path != "/v1/namespaces/default/volumes/db02":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case r.Method == http.MethodPut:
+			var opts types.VolumeUpdateOptions
+			if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+				t.Error(err)
+				return
+			}
+			count, ok := opts.Labels["storageos.feature.replicas"]
+			if !ok {
+				count = "unset"
+			}
+			counts = append(counts, count)
+			vol.Labels = opts.Labels
+		}
+		json.NewEncoder(w).Encode(vol)
+	}))
+	defer srv.Close()
+
+	client, err := api.NewVersionedClient(srv.URL, api.DefaultVersionStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := relocateReplica(ctx, client, vol, node); err != context.DeadlineExceeded {
+		t.Fatalf("expected the deadline to be exceeded, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(counts) != 2 || counts[0] != "0" || counts[1] != "unset" {
+		t.Errorf("expected the replica count to be lowered to 0 and the label removed again, got %q", counts)
+	}
+}
@@ -16,6 +16,7 @@ type healthOptions struct {
 	name    string
 	quiet   bool
 	format  string
+	output  string
 	timeout int
 }
 
@@ -35,12 +36,17 @@ func newHealthCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&opt.quiet, "quiet", "q", false, "Display minimal node health info.  Can be used with format.")
 	flags.StringVar(&opt.format, "format", "", "Pretty-print health with formats: table (default), cp, dp or raw.")
+	flags.StringVarP(&opt.output, "output", "o", "", formatter.OutputUsage)
 	flags.IntVarP(&opt.timeout, "timeout", "t", 1, "Timeout in seconds.")
 
 	return cmd
 }
 
 func runHealth(storageosCli *command.StorageOSCli, opt *healthOptions) error {
+	output, err := formatter.ParseOutput(opt.output, opt.format, opt.quiet)
+	if err != nil {
+		return err
+	}
 
 	c, err := storageosCli.Client().Controller(opt.name)
 	if err != nil {
@@ -71,6 +77,13 @@ func runHealth(storageosCli *command.StorageOSCli, opt *healthOptions) error {
 			format = formatter.TableFormatKey
 		}
 	}
+	if output.IsWide() {
+		format = formatter.WideFormatKey
+	}
+
+	if output.IsStructured() {
+		return formatter.WriteOutput(storageosCli.Out(), output, formatter.NodeHealthKind, []*cliTypes.Node{node})
+	}
 
 	nodeHealthCtx := formatter.Context{
 		Output: storageosCli.Out(),
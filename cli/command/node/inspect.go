@@ -5,11 +5,13 @@ import (
 	// storageos "github.com/storageos/go-api"
 	"github.com/storageos/go-cli/cli"
 	"github.com/storageos/go-cli/cli/command"
+	"github.com/storageos/go-cli/cli/command/formatter"
 	"github.com/storageos/go-cli/cli/command/inspect"
 )
 
 type inspectOptions struct {
 	format string
+	output string
 	names  []string
 }
 
@@ -26,7 +28,9 @@ func newInspectCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&opt.format, "format", "f", "", "Format the output using the given Go template")
+	flags := cmd.Flags()
+	flags.StringVarP(&opt.format, "format", "f", "", "Format the output using the given Go template")
+	flags.StringVarP(&opt.output, "output", "o", "", formatter.OutputUsage)
 
 	return cmd
 }
@@ -39,5 +43,9 @@ func runInspect(storageosCli *command.StorageOSCli, opt inspectOptions) error {
 		return i, nil, err
 	}
 
+	if opt.output != "" {
+		return inspect.Output(storageosCli.Out(), opt.names, opt.output, opt.format, formatter.NodeKind, getFunc)
+	}
+
 	return inspect.Inspect(storageosCli.Out(), opt.names, opt.format, getFunc)
 }
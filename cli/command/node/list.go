@@ -8,6 +8,7 @@ import (
 	"github.com/storageos/go-cli/cli"
 	"github.com/storageos/go-cli/cli/command"
 	"github.com/storageos/go-cli/cli/command/formatter"
+	"github.com/storageos/go-cli/pkg/selector"
 )
 
 type byControllerName []*types.Controller
@@ -21,6 +22,7 @@ func (r byControllerName) Less(i, j int) bool {
 type listOptions struct {
 	quiet    bool
 	format   string
+	output   string
 	selector string
 }
 
@@ -40,12 +42,23 @@ func newListCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&opt.quiet, "quiet", "q", false, "Only display node names")
 	flags.StringVar(&opt.format, "format", "", "Pretty-print nodes using a Go template")
+	flags.StringVarP(&opt.output, "output", "o", "", formatter.OutputUsage)
 	flags.StringVarP(&opt.selector, "selector", "s", "", "Provide selector (e.g. to list all nodes with label disk=ssd' --selector=disk=ssd')")
 
 	return cmd
 }
 
 func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
+	output, err := formatter.ParseOutput(opt.output, opt.format, opt.quiet)
+	if err != nil {
+		return err
+	}
+
+	sel, err := selector.Parse(opt.selector)
+	if err != nil {
+		return err
+	}
+
 	client := storageosCli.Client()
 
 	params := types.ListOptions{
@@ -56,6 +69,15 @@ func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
 	if err != nil {
 		return err
 	}
+	if !sel.Empty() {
+		matched := nodes[:0]
+		for _, node := range nodes {
+			if sel.Matches(node.Labels) {
+				matched = append(matched, node)
+			}
+		}
+		nodes = matched
+	}
 
 	format := opt.format
 	if len(format) == 0 {
@@ -65,9 +87,16 @@ func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
 			format = formatter.TableFormatKey
 		}
 	}
+	if output.IsWide() {
+		format = formatter.WideFormatKey
+	}
 
 	sort.Sort(byControllerName(nodes))
 
+	if output.IsStructured() {
+		return formatter.WriteOutput(storageosCli.Out(), output, formatter.NodeKind, nodes)
+	}
+
 	nodeCtx := formatter.Context{
 		Output: storageosCli.Out(),
 		Format: formatter.NewNodeFormat(format, opt.quiet),
@@ -0,0 +1,159 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dnephin/cobra"
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+)
+
+type removeOptions struct {
+	force bool
+	nodes []string
+}
+
+func newRemoveCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	var opt removeOptions
+
+	cmd := &cobra.Command{
+		Use:     "rm [OPTIONS] NODE [NODE...]",
+		Aliases: []string{"remove"},
+		Short:   "Remove one or more nodes from the cluster",
+		Long:    removeDescription,
+		Example: removeExample,
+		Args:    cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opt.nodes = args
+			return runRemove(storageosCli, &opt)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opt.force, "force", "f", false, "Remove nodes even if they still hold volume deployments")
+	return cmd
+}
+
+func runRemove(storageosCli *command.StorageOSCli, opt *removeOptions) error {
+	client := storageosCli.Client()
+	status := 0
+
+	// Only needed to explain why a node can't be removed, so fetch lazily.
+	var volumes []*types.Volume
+	volumesFetched := false
+
+	for _, ref := range opt.nodes {
+		node, err := client.Controller(ref)
+		if err != nil {
+			fmt.Fprintf(storageosCli.Err(), "%s\n", err)
+			status = 1
+			continue
+		}
+
+		if !opt.force {
+			if !volumesFetched {
+				volumes, err = client.VolumeList(types.ListOptions{})
+				if err != nil {
+					return err
+				}
+				volumesFetched = true
+			}
+
+			deployments := nodeDeployments(node, volumes)
+			if len(deployments) > 0 || hasVolumes(node) {
+				fmt.Fprintf(storageosCli.Err(), "%s\n", inUseError(node, deployments))
+				status = 1
+				continue
+			}
+		}
+
+		params := types.DeleteOptions{
+			Name:    node.ID,
+			Force:   opt.force,
+			Context: context.Background(),
+		}
+
+		if err := client.ControllerDelete(params); err != nil {
+			fmt.Fprintf(storageosCli.Err(), "%s\n", err)
+			status = 1
+			continue
+		}
+		fmt.Fprintf(storageosCli.Out(), "%s\n", ref)
+	}
+
+	if status != 0 {
+		return cli.StatusError{StatusCode: status}
+	}
+	return nil
+}
+
+// volumeDeployment is a master or replica of a volume placed on a node.
+type volumeDeployment struct {
+	volume *types.Volume
+	master bool
+}
+
+func (d volumeDeployment) String() string {
+	role := "replica"
+	if d.master {
+		role = "master"
+	}
+	return fmt.Sprintf("%s/%s (%s)", d.volume.Namespace, d.volume.Name, role)
+}
+
+// nodeDeployments returns the master and replica deployments of volumes that
+// are placed on node.
+func nodeDeployments(node *types.Controller, volumes []*types.Volume) []volumeDeployment {
+	onNode := func(d *types.Deployment) bool {
+		return d != nil && (d.Controller == node.ID || (d.Controller == "" && d.ControllerName == node.Name))
+	}
+
+	var deployments []volumeDeployment
+	for _, vol := range volumes {
+		if onNode(vol.Master) {
+			deployments = append(deployments, volumeDeployment{volume: vol, master: true})
+		}
+		for _, replica := range vol.Replicas {
+			if onNode(replica) {
+				deployments = append(deployments, volumeDeployment{volume: vol})
+			}
+		}
+	}
+	return deployments
+}
+
+// hasVolumes returns true if the node reports any master or replica volumes.
+func hasVolumes(node *types.Controller) bool {
+	return node.VolumeStats.MasterVolumeCount > 0 || node.VolumeStats.ReplicaVolumeCount > 0
+}
+
+func inUseError(node *types.Controller, deployments []volumeDeployment) error {
+	stats := node.VolumeStats
+	msg := fmt.Sprintf("node %s still holds %d master and %d replica volume(s), move them off the node or use --force",
+		node.Name, stats.MasterVolumeCount, stats.ReplicaVolumeCount)
+
+	if len(deployments) == 0 {
+		return errors.New(msg)
+	}
+
+	lines := make([]string, 0, len(deployments))
+	for _, d := range deployments {
+		lines = append(lines, "  "+d.String())
+	}
+	return fmt.Errorf("%s:\n%s", msg, strings.Join(lines, "\n"))
+}
+
+var removeDescription = `
+Remove one or more nodes from the cluster. A node that still holds master or
+replica volumes is not removed unless --force is given; the volumes blocking
+the removal are listed instead.
+`
+
+var removeExample = `
+$ storageos node rm storageos-3
+storageos-3
+`
@@ -0,0 +1,61 @@
+package node
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/storageos/go-api/types"
+)
+
+func TestNodeDeployments(t *testing.T) {
+	node := &types.Controller{ID: "node-1", Name: "storageos-1"}
+
+	volumes := []*types.Volume{
+		{
+			Name:      "db01",
+			Namespace: "default",
+			Master:    &types.Deployment{Controller: "node-1"},
+			Replicas:  []*types.Deployment{{Controller: "node-2"}},
+		},
+		{
+			Name:      "db02",
+			Namespace: "prod",
+			Master:    &types.Deployment{Controller: "node-2"},
+			Replicas:  []*types.Deployment{{Controller: "node-3"}, {ControllerName: "storageos-1"}},
+		},
+		{
+			Name:      "unplaced",
+			Namespace: "default",
+		},
+		{
+			Name:      "elsewhere",
+			Namespace: "default",
+			Master:    &types.Deployment{Controller: "node-3"},
+		},
+	}
+
+	deployments := nodeDeployments(node, volumes)
+
+	var got []string
+	for _, d := range deployments {
+		got = append(got, d.String())
+	}
+
+	expected := "default/db01 (master),prod/db02 (replica)"
+	if strings.Join(got, ",") != expected {
+		t.Fatalf("expected %s, got %s", expected, strings.Join(got, ","))
+	}
+}
+
+func TestInUseError(t *testing.T) {
+	node := &types.Controller{
+		Name:        "storageos-1",
+		VolumeStats: types.VolumeStats{MasterVolumeCount: 1, ReplicaVolumeCount: 1},
+	}
+	vol := &types.Volume{Name: "db01", Namespace: "default"}
+
+	err := inUseError(node, []volumeDeployment{{volume: vol, master: true}})
+	if !strings.Contains(err.Error(), "1 master and 1 replica") || !strings.Contains(err.Error(), "default/db01 (master)") {
+		t.Fatalf("unexpected error message: %v", err)
+	}
+}
@@ -0,0 +1,268 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dnephin/cobra"
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+	"github.com/storageos/go-cli/pkg/manifest"
+)
+
+const (
+	verbRead  = "read"
+	verbWrite = "write"
+)
+
+type checkOptions struct {
+	user      string
+	verb      string
+	apiGroup  string
+	resource  string
+	namespace string
+	path      string
+}
+
+func newCheckCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	opt := checkOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "check --user USER --verb VERB [OPTIONS]",
+		Short: "Check whether the policies allow a user to act on a resource",
+		Long:  checkDescription,
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCheck(storageosCli, opt)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opt.user, "user", "", "User to check")
+	flags.StringVar(&opt.verb, "verb", "", "Action to check (read|write)")
+	flags.StringVar(&opt.apiGroup, "api-group", "", "API group of the resource")
+	flags.StringVar(&opt.resource, "resource", "", "Resource to check, e.g. volumes")
+	flags.StringVar(&opt.namespace, "namespace", "", "Namespace of the resource, if it has one")
+	flags.StringVar(&opt.path, "path", "", "Non-resource path to check instead of a resource, e.g. /version")
+
+	return cmd
+}
+
+// accessRequest is an action a user asks to take.
+type accessRequest struct {
+	user      string
+	groups    []string
+	readonly  bool
+	apiGroup  string
+	resource  string
+	namespace string
+	path      string
+}
+
+// String describes the action, such as "write volumes in namespace prod".
+func (r accessRequest) String() string {
+	verb := verbWrite
+	if r.readonly {
+		verb = verbRead
+	}
+	if r.path != "" {
+		return verb + " " + r.path
+	}
+
+	s := verb + " "
+	if r.resource != "" {
+		s += r.resource
+	} else {
+		s += "any resource"
+	}
+	if r.apiGroup != "" {
+		s += " of API group " + r.apiGroup
+	}
+	if r.namespace != "" {
+		s += " in namespace " + r.namespace
+	}
+	return s
+}
+
+// verdict is whether a policy for the user grants a request, and if not,
+// why not.
+type verdict struct {
+	id      string
+	spec    manifest.PolicySpec
+	granted bool
+	reason  string
+}
+
+func runCheck(storageosCli *command.StorageOSCli, opt checkOptions) error {
+	if opt.user == "" {
+		return errors.New("Please provide the user to check with --user")
+	}
+	if opt.path != "" && (opt.apiGroup+opt.resource+opt.namespace) != "" {
+		return errors.New("Conflicting options: either specify --path or a resource, not both")
+	}
+
+	req := accessRequest{
+		user:      opt.user,
+		apiGroup:  opt.apiGroup,
+		resource:  opt.resource,
+		namespace: opt.namespace,
+		path:      opt.path,
+	}
+	switch opt.verb {
+	case verbRead:
+		req.readonly = true
+	case verbWrite:
+	default:
+		return fmt.Errorf("invalid verb %q, expected %s or %s", opt.verb, verbRead, verbWrite)
+	}
+
+	client := storageosCli.Client()
+
+	user, err := client.User(opt.user)
+	if err != nil {
+		return err
+	}
+	for _, g := range user.Groups {
+		if g != "" {
+			req.groups = append(req.groups, g)
+		}
+	}
+
+	set, err := client.PolicyList(types.ListOptions{})
+	if err != nil {
+		return err
+	}
+	policies := set.GetPoliciesWithID()
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].ID < policies[j].ID
+	})
+
+	allowed, verdicts := evaluate(req, policies)
+	if user.Role == "admin" {
+		allowed = true
+	}
+
+	writeVerdicts(storageosCli.Out(), req, user.Role, allowed, verdicts)
+	if !allowed {
+		return cli.StatusError{StatusCode: 1}
+	}
+	return nil
+}
+
+// evaluate returns whether the policies allow the request, and a verdict
+// for each policy of the user or their groups.
+func evaluate(req accessRequest, policies []*types.PolicyWithID) (bool, []verdict) {
+	allowed := false
+	var verdicts []verdict
+	for _, p := range policies {
+		spec := manifest.PolicySpec(p.Spec)
+		if !subjectMatches(spec, req) {
+			continue
+		}
+		v := verdict{id: p.ID, spec: spec}
+		v.reason = mismatch(spec, req)
+		v.granted = v.reason == ""
+		allowed = allowed || v.granted
+		verdicts = append(verdicts, v)
+	}
+	return allowed, verdicts
+}
+
+// subjectMatches returns true if the policy is for the user or one of their
+// groups.
+func subjectMatches(spec manifest.PolicySpec, req accessRequest) bool {
+	if spec.User == "*" || (spec.User != "" && spec.User == req.user) {
+		return true
+	}
+	if spec.Group == "*" {
+		return true
+	}
+	for _, g := range req.groups {
+		if spec.Group != "" && spec.Group == g {
+			return true
+		}
+	}
+	return false
+}
+
+// mismatch returns why the policy doesn't grant the request, or "" if it
+// does. Empty fields of a policy, and fields set to *, match anything,
+// except that only policies with a nonResourcePath grant access to
+// non-resource paths.
+func mismatch(spec manifest.PolicySpec, req accessRequest) string {
+	if spec.Readonly && !req.readonly {
+		return "the policy is readonly"
+	}
+
+	if req.path != "" {
+		switch {
+		case spec.NonResourcePath == "":
+			return "the policy has no nonResourcePath"
+		case spec.NonResourcePath == "*", spec.NonResourcePath == req.path:
+			return ""
+		case strings.HasSuffix(spec.NonResourcePath, "*") && strings.HasPrefix(req.path, strings.TrimSuffix(spec.NonResourcePath, "*")):
+			return ""
+		}
+		return fmt.Sprintf("nonResourcePath %s doesn't match %s", spec.NonResourcePath, req.path)
+	}
+
+	for _, field := range []struct {
+		name, policy, request string
+	}{
+		{"apiGroup", spec.APIGroup, req.apiGroup},
+		{"resource", spec.Resource, req.resource},
+		{"namespace", spec.Namespace, req.namespace},
+	} {
+		if field.policy == "" || field.policy == "*" || field.policy == field.request {
+			continue
+		}
+		if field.request == "" {
+			return fmt.Sprintf("%s is limited to %s", field.name, field.policy)
+		}
+		return fmt.Sprintf("%s %s doesn't match %s", field.name, field.policy, field.request)
+	}
+	return ""
+}
+
+func writeVerdicts(out io.Writer, req accessRequest, role string, allowed bool, verdicts []verdict) {
+	if allowed {
+		fmt.Fprintf(out, "%s can %s\n", req.user, req)
+	} else {
+		fmt.Fprintf(out, "%s can't %s\n", req.user, req)
+	}
+
+	if role == "admin" {
+		fmt.Fprintf(out, "  %s is an admin, and so isn't limited by policies\n", req.user)
+	}
+	if len(verdicts) == 0 {
+		if len(req.groups) > 0 {
+			fmt.Fprintf(out, "  no policy is for %s or the groups %s\n", req.user, strings.Join(req.groups, ", "))
+		} else {
+			fmt.Fprintf(out, "  no policy is for %s, who has no groups\n", req.user)
+		}
+	}
+	for _, v := range verdicts {
+		if v.granted {
+			fmt.Fprintf(out, "  granted by policy %s (%s)\n", v.id, v.spec.String())
+			continue
+		}
+		fmt.Fprintf(out, "  not granted by policy %s (%s): %s\n", v.id, v.spec.String(), v.reason)
+	}
+}
+
+var checkDescription = `
+Check whether the policies allow a user to read or write a resource, or a
+non-resource path, evaluating the policies of the user and their groups
+locally. Each of those policies is listed with whether it grants the access,
+and if not, why not. The exit status is 1 when the access is denied.
+
+Empty fields of a policy, and fields set to *, match anything, except that
+only policies with a nonResourcePath grant access to non-resource paths.
+Readonly policies only grant reads. Admins aren't limited by policies.
+
+  storageos policy check --user alice --verb write --resource volumes --namespace prod
+`
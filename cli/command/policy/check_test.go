@@ -0,0 +1,52 @@
+package policy
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+func testPolicy(id, user, group, namespace string, readonly bool) *types.PolicyWithID {
+	p := &types.PolicyWithID{ID: id}
+	p.Spec.User = user
+	p.Spec.Group = group
+	p.Spec.Namespace = namespace
+	p.Spec.Readonly = readonly
+	return p
+}
+
+func TestEvaluate(t *testing.T) {
+	policies := []*types.PolicyWithID{
+		testPolicy("1", "alice", "", "", true),
+		testPolicy("2", "", "devs", "prod", false),
+		testPolicy("3", "bob", "", "", false),
+	}
+	req := accessRequest{user: "alice", groups: []string{"devs"}, resource: "volumes", namespace: "dev"}
+
+	allowed, verdicts := evaluate(req, policies)
+	assert.Equal(t, allowed, false)
+	assert.Equal(t, len(verdicts), 2)
+
+	var out bytes.Buffer
+	writeVerdicts(&out, req, "user", allowed, verdicts)
+	assert.Equal(t, out.String(), `alice can't write volumes in namespace dev
+  not granted by policy 1 (user=alice,readonly=true): the policy is readonly
+  not granted by policy 2 (group=devs,namespace=prod): namespace prod doesn't match dev
+`)
+
+	req.namespace = "prod"
+	allowed, verdicts = evaluate(req, policies)
+	assert.Equal(t, allowed, true)
+	assert.Equal(t, verdicts[1].granted, true)
+
+	req.readonly, req.namespace = true, ""
+	allowed, _ = evaluate(req, policies)
+	assert.Equal(t, allowed, true)
+
+	req.resource, req.path = "", "/version"
+	allowed, verdicts = evaluate(req, policies)
+	assert.Equal(t, allowed, false)
+	assert.Equal(t, verdicts[0].reason, "the policy has no nonResourcePath")
+}
@@ -20,6 +20,7 @@ func NewPolicyCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 		command.WithAlias(newInspectCommand(storageosCli), command.InspectAliases...),
 		command.WithAlias(newListCommand(storageosCli), command.ListAliases...),
 		command.WithAlias(newRemoveCommand(storageosCli), command.RemoveAliases...),
+		newCheckCommand(storageosCli),
 	)
 	return cmd
 }
@@ -1,7 +1,6 @@
 package policy
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -44,7 +43,7 @@ func newCreateCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 		Use: "create [OPTIONS]",
 		Short: `Create a new policy, Either provide the set of policy files, set with options or write to stdin.
 		E.g. "storageos policy create --user awesomeUser --namespace testing"
-		E.g. "storageos policy create --policies='rules1.jsonl,rules2.jsonl'"
+		E.g. "storageos policy create --policies='rules1.yaml,rules2.jsonl'"
 		E.g. "echo '{"spec": {"group": "devs", "namespace": "develop"}}' | storageos policy create --stdin"`,
 		Args: cli.ExactArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -56,7 +55,7 @@ func newCreateCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 	flags.StringVar(&opt.user, "user", "", "User field for a policy entry")
 	flags.StringVar(&opt.group, "group", "", "Group field for a policy entry")
 	flags.StringVar(&opt.namespace, "namespace", "", "Namespace field for a policy entry")
-	flags.Var(&opt.policies, "policies", "Provide a new (comma seperated) list of policy files in json line format.")
+	flags.Var(&opt.policies, "policies", "Provide a new (comma seperated) list of policy files in YAML, JSON or json line format.")
 	flags.BoolVar(&opt.stdin, "stdin", false, "Read policy input from stdin")
 	return cmd
 }
@@ -88,54 +87,8 @@ func runCreate(cmd *cobra.Command, storageosCli *command.StorageOSCli, opt creat
 	}
 }
 
-// Attempts to parse JSONish data (multiple formats) to jsonl format
-func jsonlParse(data []byte) ([]byte, error) {
-	type jsonList []*json.RawMessage
-
-	isJSONType := func(t interface{}, data []byte) bool {
-		return json.Unmarshal(data, t) == nil
-	}
-
-	isJSONL := func(data []byte) bool {
-		split := bytes.Split(data, []byte("\n"))
-		obj := &json.RawMessage{}
-		for _, elm := range split {
-			if !isJSONType(obj, elm) {
-				return false
-			}
-		}
-		return true
-	}
-
-	if isJSONL(data) {
-		return data, nil
-	}
-
-	list := jsonList{}
-	obj := &json.RawMessage{}
-
-	var concat []byte
-	if isJSONType(&list, data) {
-		for _, obj := range list {
-			deref := *((*[]byte)(obj))
-			concat = append(concat, deref...)
-			concat = append(concat, '\n')
-		}
-		return concat, nil
-	}
-
-	if isJSONType(&obj, data) {
-		deref := *((*[]byte)(obj))
-		concat = append(concat, deref...)
-		concat = append(concat, '\n')
-		return concat, nil
-	}
-
-	return nil, fmt.Errorf("unknown data format")
-}
-
 func runCreateFromFiles(storageosCli *command.StorageOSCli, opt createOptions) error {
-	var jsonlFiles [][]byte
+	var policies []types.Policy
 
 	for _, file := range opt.policies {
 		buf, err := ioutil.ReadFile(file)
@@ -143,15 +96,15 @@ func runCreateFromFiles(storageosCli *command.StorageOSCli, opt createOptions) e
 			return fmt.Errorf("failed to read policy file (No. %s): %s", file, err)
 		}
 
-		jsonlBuf, err := jsonlParse(buf)
+		parsed, err := parsePolicies(buf, file)
 		if err != nil {
-			return fmt.Errorf("failed to parse policy file (No. %s): %s", file, err)
+			return err
 		}
 
-		jsonlFiles = append(jsonlFiles, jsonlBuf)
+		policies = append(policies, parsed...)
 	}
 
-	return sendJSONL(storageosCli, bytes.Join(jsonlFiles, []byte("\n")))
+	return sendPolicies(storageosCli, policies)
 }
 
 func runCreateFromFlags(storageosCli *command.StorageOSCli, opt createOptions) error {
@@ -160,12 +113,7 @@ func runCreateFromFlags(storageosCli *command.StorageOSCli, opt createOptions) e
 	pol.Spec.Group = opt.group
 	pol.Spec.Namespace = opt.namespace
 
-	data, err := json.Marshal(&pol)
-	if err != nil {
-		return err
-	}
-
-	return sendJSONL(storageosCli, append(data, '\n'))
+	return sendPolicies(storageosCli, []types.Policy{pol})
 }
 
 func runCreateFromStdin(storageosCli *command.StorageOSCli, opt createOptions) error {
@@ -174,14 +122,23 @@ func runCreateFromStdin(storageosCli *command.StorageOSCli, opt createOptions) e
 		return fmt.Errorf("failed to read stdin: %s", err)
 	}
 
-	jsonlBuf, err := jsonlParse(buf)
+	policies, err := parsePolicies(buf, "<stdin>")
 	if err != nil {
-		return fmt.Errorf("failed to parse stdin: %s", err)
+		return err
 	}
 
-	return sendJSONL(storageosCli, jsonlBuf)
+	return sendPolicies(storageosCli, policies)
 }
 
-func sendJSONL(storageosCli *command.StorageOSCli, jsonl []byte) error {
+// sendPolicies creates the policies, sent as JSON lines.
+func sendPolicies(storageosCli *command.StorageOSCli, policies []types.Policy) error {
+	var jsonl []byte
+	for i := range policies {
+		data, err := json.Marshal(&policies[i])
+		if err != nil {
+			return err
+		}
+		jsonl = append(append(jsonl, data...), '\n')
+	}
 	return storageosCli.Client().PolicyCreate(jsonl, context.Background())
 }
@@ -0,0 +1,317 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/cli/command/formatter"
+	"github.com/storageos/go-cli/pkg/manifest"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// policyEntry is a policy as written in policy files. Only the spec is
+// read: the other fields are allowed so that ABAC-style lines and the items
+// written by policy ls -o json or yaml can be read back.
+type policyEntry struct {
+	APIVersion string              `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
+	Kind       string              `json:"kind,omitempty" yaml:"kind,omitempty"`
+	ID         string              `json:"id,omitempty" yaml:"id,omitempty"`
+	Spec       manifest.PolicySpec `json:"spec" yaml:"spec"`
+}
+
+// policyList is the envelope written by policy ls -o json or yaml.
+type policyList struct {
+	APIVersion string        `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string        `json:"kind" yaml:"kind"`
+	Items      []policyEntry `json:"items" yaml:"items"`
+}
+
+const (
+	policyKind     = formatter.PolicyKind
+	policyListKind = formatter.PolicyKind + "List"
+)
+
+// parsePolicies reads the policies in data, which holds JSON lines, a JSON
+// array or object, or YAML documents each holding a policy or a list of
+// policies. A list can also be the envelope written by policy ls -o json or
+// yaml. Every policy is checked, and errors give the line of the policy
+// in source.
+func parsePolicies(data []byte, source string) ([]types.Policy, error) {
+	var entries []policyEntry
+	var lines []int
+	var err error
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case isJSONLines(trimmed):
+		entries, lines, err = parseJSONLines(data, source)
+	case json.Valid(trimmed):
+		entries, lines, err = parseJSON(data, source)
+	default:
+		entries, lines, err = parseYAML(data, source)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%s: no policies found", source)
+	}
+
+	policies := make([]types.Policy, len(entries))
+	for i, e := range entries {
+		if e.Kind != "" && e.Kind != policyKind {
+			return nil, fmt.Errorf("%s:%d: kind %s is not a %s", source, lines[i], e.Kind, policyKind)
+		}
+		if err := validatePolicy(e.Spec); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", source, lines[i], err)
+		}
+		policies[i] = e.Spec.Policy()
+	}
+	return policies, nil
+}
+
+func validatePolicy(spec manifest.PolicySpec) error {
+	if spec.User == "" && spec.Group == "" {
+		return fmt.Errorf("policy needs a user or a group")
+	}
+	if p := spec.NonResourcePath; p != "" && p != "*" && !strings.HasPrefix(p, "/") {
+		return fmt.Errorf("policy nonResourcePath %q must be * or start with /", p)
+	}
+	return nil
+}
+
+// isJSONLines returns true if every line of data is a JSON object.
+func isJSONLines(data []byte) bool {
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] != '{' || !json.Valid(line) {
+			return false
+		}
+	}
+	return true
+}
+
+func decodeJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+func parseJSONLines(data []byte, source string) ([]policyEntry, []int, error) {
+	var entries []policyEntry
+	var lines []int
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var e policyEntry
+		if err := decodeJSON(line, &e); err != nil {
+			return nil, nil, fmt.Errorf("%s:%d: %v", source, i+1, err)
+		}
+		entries = append(entries, e)
+		lines = append(lines, i+1)
+	}
+	return entries, lines, nil
+}
+
+func parseJSON(data []byte, source string) ([]policyEntry, []int, error) {
+	start := len(data) - len(bytes.TrimLeft(data, " \t\r\n"))
+	if data[start] == '{' && hasItems(data) {
+		return parseJSONList(data, source)
+	}
+	if data[start] != '[' {
+		var e policyEntry
+		if err := decodeJSON(data, &e); err != nil {
+			return nil, nil, fmt.Errorf("%s:%d: %v", source, lineAt(data, start), err)
+		}
+		return []policyEntry{e}, []int{lineAt(data, start)}, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return decodeJSONItems(dec, data, source)
+}
+
+// hasItems returns true if data holds a JSON object with an items key.
+func hasItems(data []byte) bool {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return false
+	}
+	_, ok := keys["items"]
+	return ok
+}
+
+// parseJSONList reads the policies of a policy list envelope, keeping the
+// line of each item.
+func parseJSONList(data []byte, source string) ([]policyEntry, []int, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", source, err)
+	}
+
+	var entries []policyEntry
+	var lines []int
+	for dec.More() {
+		line := lineAt(data, int(dec.InputOffset()))
+		key, err := dec.Token()
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s:%d: %v", source, line, err)
+		}
+		switch key {
+		case "apiVersion", "kind":
+			var value string
+			if err := dec.Decode(&value); err != nil {
+				return nil, nil, fmt.Errorf("%s:%d: %v", source, line, err)
+			}
+			if key == "kind" && value != policyListKind {
+				return nil, nil, fmt.Errorf("%s:%d: kind %s is not a %s", source, line, value, policyListKind)
+			}
+		case "items":
+			entries, lines, err = decodeJSONItems(dec, data, source)
+			if err != nil {
+				return nil, nil, err
+			}
+		default:
+			return nil, nil, fmt.Errorf("%s:%d: json: unknown field %q", source, line, key)
+		}
+	}
+	return entries, lines, nil
+}
+
+// decodeJSONItems reads the policies of the JSON array starting at the next
+// token of dec, keeping the line of each policy.
+func decodeJSONItems(dec *json.Decoder, data []byte, source string) ([]policyEntry, []int, error) {
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", source, err)
+	}
+
+	var entries []policyEntry
+	var lines []int
+	for dec.More() {
+		line := lineAt(data, int(dec.InputOffset()))
+		var e policyEntry
+		if err := dec.Decode(&e); err != nil {
+			return nil, nil, fmt.Errorf("%s:%d: %v", source, line, err)
+		}
+		entries = append(entries, e)
+		lines = append(lines, line)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", source, err)
+	}
+	return entries, lines, nil
+}
+
+// lineAt returns the line of the first value at or after offset.
+func lineAt(data []byte, offset int) int {
+	for offset < len(data) && strings.IndexByte(" \t\r\n,", data[offset]) >= 0 {
+		offset++
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+func parseYAML(data []byte, source string) ([]policyEntry, []int, error) {
+	var entries []policyEntry
+	var lines []int
+	for _, doc := range manifest.SplitDocuments(data) {
+		var v interface{}
+		if err := yaml.Unmarshal(doc.Data, &v); err != nil {
+			return nil, nil, manifest.YAMLError(err, source, doc.Line)
+		}
+
+		switch v.(type) {
+		case nil:
+			// Empty document, or only comments.
+		case []interface{}:
+			var list []policyEntry
+			if err := yaml.UnmarshalStrict(doc.Data, &list); err != nil {
+				return nil, nil, manifest.YAMLError(err, source, doc.Line)
+			}
+			entries, lines = appendItems(entries, lines, list, doc)
+		default:
+			if m, ok := v.(map[interface{}]interface{}); ok && m["items"] != nil {
+				var l policyList
+				if err := yaml.UnmarshalStrict(doc.Data, &l); err != nil {
+					return nil, nil, manifest.YAMLError(err, source, doc.Line)
+				}
+				if l.Kind != policyListKind {
+					return nil, nil, fmt.Errorf("%s:%d: kind %s is not a %s", source, contentLine(doc), l.Kind, policyListKind)
+				}
+				entries, lines = appendItems(entries, lines, l.Items, doc)
+				continue
+			}
+			var e policyEntry
+			if err := yaml.UnmarshalStrict(doc.Data, &e); err != nil {
+				return nil, nil, manifest.YAMLError(err, source, doc.Line)
+			}
+			entries = append(entries, e)
+			lines = append(lines, contentLine(doc))
+		}
+	}
+	return entries, lines, nil
+}
+
+// appendItems appends the policies of a list in doc and their lines, which
+// are only known for block lists, one item per "- ".
+func appendItems(entries []policyEntry, lines []int, list []policyEntry, doc manifest.Document) ([]policyEntry, []int) {
+	items := itemLines(doc)
+	for i, e := range list {
+		entries = append(entries, e)
+		if len(items) == len(list) {
+			lines = append(lines, items[i])
+		} else {
+			lines = append(lines, doc.Line)
+		}
+	}
+	return entries, lines
+}
+
+// contentLine returns the first line of the document that isn't blank or
+// a comment.
+func contentLine(doc manifest.Document) int {
+	for i, line := range strings.Split(string(doc.Data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return doc.Line + i
+		}
+	}
+	return doc.Line
+}
+
+// itemLines returns the lines of the items of a document holding a block
+// list.
+func itemLines(doc manifest.Document) []int {
+	var lines []int
+	for i, line := range strings.Split(string(doc.Data), "\n") {
+		if line == "-" || strings.HasPrefix(line, "- ") {
+			lines = append(lines, doc.Line+i)
+		}
+	}
+	return lines
+}
+
+// writePolicies writes policies as a YAML list read by parsePolicies,
+// sorted so that the output is stable.
+func writePolicies(out io.Writer, policies []types.Policy) error {
+	entries := make([]policyEntry, len(policies))
+	for i, p := range policies {
+		entries[i] = policyEntry{Spec: manifest.PolicySpec(p.Spec)}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Spec.String() < entries[j].Spec.String()
+	})
+
+	data, err := yaml.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(data)
+	return err
+}
@@ -0,0 +1,102 @@
+package policy
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/cli/command/formatter"
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+const testPolicies = `# Developers work in develop, and can read prod.
+spec:
+  group: devs
+  namespace: develop
+---
+# Operators
+- spec:
+    group: ops
+- spec:
+    # Read-only access for the monitoring user.
+    user: monitor
+    readonly: true
+`
+
+func TestParsePolicies(t *testing.T) {
+	policies, err := parsePolicies([]byte(testPolicies), "test.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, len(policies), 3)
+	assert.Equal(t, policies[0].Spec.Namespace, "develop")
+	assert.Equal(t, policies[2].Spec.Readonly, true)
+
+	// What policy ls --export writes is read back.
+	var buf bytes.Buffer
+	assert.NilError(t, writePolicies(&buf, policies))
+	again, err := parsePolicies(buf.Bytes(), "export.yaml")
+	assert.NilError(t, err)
+	assert.Equal(t, len(again), 3)
+	assert.Equal(t, buf.String(), `- spec:
+    group: devs
+    namespace: develop
+- spec:
+    group: ops
+- spec:
+    user: monitor
+    readonly: true
+`)
+
+	for _, data := range []string{
+		"{\"spec\": {\"user\": \"a\"}}\n{\"spec\": {\"group\": \"b\"}}\n",
+		"[\n\t{\"spec\": {\"user\": \"a\"}},\n\t{\"spec\": {\"group\": \"b\"}}\n]\n",
+	} {
+		policies, err = parsePolicies([]byte(data), "test.json")
+		assert.NilError(t, err)
+		assert.Equal(t, len(policies), 2)
+		assert.Equal(t, policies[1].Spec.Group, "b")
+	}
+}
+
+func TestParsePoliciesAPIVersionKind(t *testing.T) {
+	// ABAC-style lines, with apiVersion and kind around the spec.
+	data := `{"apiVersion": "abac.authorization.kubernetes.io/v1beta1", "kind": "Policy", "spec": {"user": "alice", "namespace": "prod"}}
+{"apiVersion": "abac.authorization.kubernetes.io/v1beta1", "kind": "Policy", "spec": {"group": "ops", "readonly": true}}
+`
+	policies, err := parsePolicies([]byte(data), "abac.jsonl")
+	assert.NilError(t, err)
+	assert.Equal(t, len(policies), 2)
+	assert.Equal(t, policies[0].Spec.User, "alice")
+	assert.Equal(t, policies[1].Spec.Readonly, true)
+
+	// What policy ls -o json or yaml writes is read back.
+	written := types.PolicySet{"a1": policies[0], "b2": policies[1]}
+	for _, key := range []string{formatter.JSONOutputKey, formatter.YAMLOutputKey} {
+		var buf bytes.Buffer
+		assert.NilError(t, formatter.WriteOutput(&buf, formatter.Output{Key: key}, formatter.PolicyKind, written.GetPoliciesWithID()))
+		again, err := parsePolicies(buf.Bytes(), "ls."+key)
+		assert.NilError(t, err)
+		assert.Equal(t, len(again), 2)
+	}
+}
+
+func TestParsePoliciesErrors(t *testing.T) {
+	for _, tt := range []struct {
+		data string
+		err  string
+	}{
+		{data: testPolicies + "- spec:\n    namespace: prod\n", err: "test.yaml:13: policy needs a user or a group"},
+		{data: "# Nobody\n\nspec:\n  usr: alice\n", err: "test.yaml:4: field usr not found"},
+		{data: "spec:\n  user: alice\n  readonly: maybe\n", err: "test.yaml:3: cannot unmarshal !!str `maybe` into bool"},
+		{data: "spec:\n  user: alice\n  nonResourcePath: version\n", err: `test.yaml:1: policy nonResourcePath "version" must be * or start with /`},
+		{data: "{\"spec\": {\"user\": \"a\"}}\n{\"spec\": {\"usr\": \"b\"}}\n", err: `test.yaml:2: json: unknown field "usr"`},
+		{data: "[\n  {\"spec\": {\"user\": \"a\"}},\n  {\"spec\": {}}\n]", err: "test.yaml:3: policy needs a user or a group"},
+		{data: "# Nothing yet\n", err: "test.yaml: no policies found"},
+		{data: "{\"kind\": \"Policy\", \"spec\": {\"user\": \"a\"}}\n{\"kind\": \"Pool\", \"spec\": {\"user\": \"b\"}}\n", err: "test.yaml:2: kind Pool is not a Policy"},
+		{data: "{\"metadata\": {}, \"spec\": {\"user\": \"a\"}}\n", err: `test.yaml:1: json: unknown field "metadata"`},
+		{data: "{\"kind\": \"PolicyList\", \"items\": [\n  {\"spec\": {\"user\": \"a\"}},\n  {\"spec\": {\"usr\": \"b\"}}\n]}\n", err: `test.yaml:3: json: unknown field "usr"`},
+		{data: "kind: PoolList\nitems:\n- spec:\n    user: a\n", err: "test.yaml:1: kind PoolList is not a PolicyList"},
+	} {
+		_, err := parsePolicies([]byte(tt.data), "test.yaml")
+		assert.Error(t, err, tt.err)
+	}
+}
@@ -2,13 +2,16 @@ package policy
 
 import (
 	"github.com/dnephin/cobra"
+	"github.com/storageos/go-api/types"
 	"github.com/storageos/go-cli/cli"
 	"github.com/storageos/go-cli/cli/command"
+	"github.com/storageos/go-cli/cli/command/formatter"
 	"github.com/storageos/go-cli/cli/command/inspect"
 )
 
 type inspectOptions struct {
 	format   string
+	output   string
 	policies []string
 }
 
@@ -25,7 +28,9 @@ func newInspectCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&opt.format, "format", "f", "", "Format the output using the given Go template")
+	flags := cmd.Flags()
+	flags.StringVarP(&opt.format, "format", "f", "", "Format the output using the given Go template")
+	flags.StringVarP(&opt.output, "output", "o", "", formatter.OutputUsage)
 
 	return cmd
 }
@@ -38,5 +43,18 @@ func runInspect(storageosCli *command.StorageOSCli, opt inspectOptions) error {
 		return i, nil, err
 	}
 
+	if opt.output != "" {
+		// The ID of a policy is only part of its structured output when
+		// it is kept alongside it.
+		getWithID := func(ref string) (interface{}, []byte, error) {
+			p, err := client.Policy(ref)
+			if err != nil {
+				return nil, nil, err
+			}
+			return &types.PolicyWithID{ID: ref, Policy: *p}, nil, nil
+		}
+		return inspect.Output(storageosCli.Out(), opt.policies, opt.output, opt.format, formatter.PolicyKind, getWithID)
+	}
+
 	return inspect.Inspect(storageosCli.Out(), opt.policies, opt.format, getFunc)
 }
@@ -1,6 +1,8 @@
 package policy
 
 import (
+	"errors"
+
 	"github.com/dnephin/cobra"
 	"github.com/storageos/go-api/types"
 	"github.com/storageos/go-cli/cli"
@@ -10,6 +12,8 @@ import (
 
 type listOptions struct {
 	format string
+	output string
+	export bool
 }
 
 func newListCommand(storageosCli *command.StorageOSCli) *cobra.Command {
@@ -27,11 +31,21 @@ func newListCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.StringVar(&opt.format, "format", "", "Pretty-print rules using a Go template")
+	flags.StringVarP(&opt.output, "output", "o", "", formatter.OutputUsage)
+	flags.BoolVar(&opt.export, "export", false, "Write the policies as YAML, to be read by 'policy create --policies'")
 
 	return cmd
 }
 
 func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
+	output, err := formatter.ParseOutput(opt.output, opt.format, false)
+	if err != nil {
+		return err
+	}
+	if opt.export && (opt.output != "" || opt.format != "") {
+		return errors.New("Conflicting options: --export cannot be used with --format or --output")
+	}
+
 	client := storageosCli.Client()
 
 	params := types.ListOptions{}
@@ -41,6 +55,14 @@ func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
 		return err
 	}
 
+	if opt.export {
+		var list []types.Policy
+		for _, p := range policies {
+			list = append(list, p)
+		}
+		return writePolicies(storageosCli.Out(), list)
+	}
+
 	format := opt.format
 	if len(format) == 0 {
 		if len(storageosCli.ConfigFile().PoliciesFormat) > 0 {
@@ -49,6 +71,13 @@ func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
 			format = formatter.TableFormatKey
 		}
 	}
+	if output.IsWide() {
+		format = formatter.WideFormatKey
+	}
+
+	if output.IsStructured() {
+		return formatter.WriteOutput(storageosCli.Out(), output, formatter.PolicyKind, policies.GetPoliciesWithID())
+	}
 
 	return formatter.PolicyWrite(formatter.Context{
 		Output: storageosCli.Out(),
@@ -20,6 +20,7 @@ func NewPoolCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 		command.WithAlias(newInspectCommand(storageosCli), command.InspectAliases...),
 		command.WithAlias(newListCommand(storageosCli), command.ListAliases...),
 		command.WithAlias(newRemoveCommand(storageosCli), command.RemoveAliases...),
+		command.WithAlias(newUpdateCommand(storageosCli), command.UpdateAliases...),
 	)
 	return cmd
 }
@@ -4,11 +4,13 @@ import (
 	"github.com/dnephin/cobra"
 	"github.com/storageos/go-cli/cli"
 	"github.com/storageos/go-cli/cli/command"
+	"github.com/storageos/go-cli/cli/command/formatter"
 	"github.com/storageos/go-cli/cli/command/inspect"
 )
 
 type inspectOptions struct {
 	format string
+	output string
 	names  []string
 }
 
@@ -25,7 +27,9 @@ func newInspectCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&opt.format, "format", "f", "", "Format the output using the given Go template")
+	flags := cmd.Flags()
+	flags.StringVarP(&opt.format, "format", "f", "", "Format the output using the given Go template")
+	flags.StringVarP(&opt.output, "output", "o", "", formatter.OutputUsage)
 
 	return cmd
 }
@@ -38,5 +42,9 @@ func runInspect(storageosCli *command.StorageOSCli, opt inspectOptions) error {
 		return i, nil, err
 	}
 
+	if opt.output != "" {
+		return inspect.Output(storageosCli.Out(), opt.names, opt.output, opt.format, formatter.PoolKind, getFunc)
+	}
+
 	return inspect.Inspect(storageosCli.Out(), opt.names, opt.format, getFunc)
 }
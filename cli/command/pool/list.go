@@ -8,6 +8,7 @@ import (
 	"github.com/storageos/go-cli/cli"
 	"github.com/storageos/go-cli/cli/command"
 	"github.com/storageos/go-cli/cli/command/formatter"
+	"github.com/storageos/go-cli/pkg/selector"
 )
 
 type byPoolName []*types.Pool
@@ -21,6 +22,7 @@ func (r byPoolName) Less(i, j int) bool {
 type listOptions struct {
 	quiet    bool
 	format   string
+	output   string
 	selector string
 }
 
@@ -40,12 +42,23 @@ func newListCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&opt.quiet, "quiet", "q", false, "Only display pool names")
 	flags.StringVar(&opt.format, "format", "", "Pretty-print pools using a Go template")
+	flags.StringVarP(&opt.output, "output", "o", "", formatter.OutputUsage)
 	flags.StringVarP(&opt.selector, "selector", "s", "", "Provide selector (e.g. to list all pools with label app=cassandra ' --selector=app=cassandra')")
 
 	return cmd
 }
 
 func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
+	output, err := formatter.ParseOutput(opt.output, opt.format, opt.quiet)
+	if err != nil {
+		return err
+	}
+
+	sel, err := selector.Parse(opt.selector)
+	if err != nil {
+		return err
+	}
+
 	client := storageosCli.Client()
 
 	params := types.ListOptions{
@@ -57,6 +70,15 @@ func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
 	if err != nil {
 		return err
 	}
+	if !sel.Empty() {
+		matched := pools[:0]
+		for _, pool := range pools {
+			if sel.Matches(pool.Labels) {
+				matched = append(matched, pool)
+			}
+		}
+		pools = matched
+	}
 
 	format := opt.format
 	if len(format) == 0 {
@@ -66,9 +88,16 @@ func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
 			format = formatter.TableFormatKey
 		}
 	}
+	if output.IsWide() {
+		format = formatter.WideFormatKey
+	}
 
 	sort.Sort(byPoolName(pools))
 
+	if output.IsStructured() {
+		return formatter.WriteOutput(storageosCli.Out(), output, formatter.PoolKind, pools)
+	}
+
 	poolCtx := formatter.Context{
 		Output: storageosCli.Out(),
 		Format: formatter.NewPoolFormat(format, opt.quiet),
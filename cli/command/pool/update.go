@@ -0,0 +1,245 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/dnephin/cobra"
+	units "github.com/docker/go-units"
+	"github.com/spf13/pflag"
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+	"github.com/storageos/go-cli/cli/opts"
+)
+
+const (
+	flagControllerAdd    = "controller-add"
+	flagControllerRemove = "controller-rm"
+	flagDriverAdd        = "driver-add"
+	flagDriverRemove     = "driver-rm"
+	flagDefault          = "default"
+	flagActive           = "active"
+	flagLabelAdd         = "label-add"
+	flagLabelRemove      = "label-rm"
+)
+
+type updateOptions struct {
+	controllers opts.ListOpts
+	drivers     opts.ListOpts
+	isDefault   bool
+	active      bool
+	labels      opts.ListOpts
+}
+
+func newUpdateCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	opt := updateOptions{
+		controllers: opts.NewListOpts(nil),
+		drivers:     opts.NewListOpts(nil),
+		labels:      opts.NewListOpts(opts.ValidateEnv),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "update [OPTIONS] POOL",
+		Short: "Update a capacity pool",
+		Long:  updateDescription,
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUpdate(storageosCli, cmd.Flags(), args[0])
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.Var(&opt.controllers, flagControllerAdd, "Add a controller that contributes capacity to the pool")
+	controllerNames := opts.NewListOpts(nil)
+	flags.Var(&controllerNames, flagControllerRemove, "Remove a controller from the pool")
+	flags.Var(&opt.drivers, flagDriverAdd, "Add a driver providing capacity to the pool")
+	driverNames := opts.NewListOpts(nil)
+	flags.Var(&driverNames, flagDriverRemove, "Remove a driver from the pool")
+	flags.BoolVar(&opt.isDefault, flagDefault, false, "Set as default pool")
+	flags.BoolVar(&opt.active, flagActive, true, "Enable or disable the pool")
+	flags.Var(&opt.labels, flagLabelAdd, "Add or update a pool label (key=value)")
+	labelKeys := opts.NewListOpts(nil)
+	flags.Var(&labelKeys, flagLabelRemove, "Remove a pool label if exists")
+	return cmd
+}
+
+func runUpdate(storageosCli *command.StorageOSCli, flags *pflag.FlagSet, name string) error {
+	success := func(_ string) {
+		fmt.Fprintln(storageosCli.Out(), name)
+	}
+	return updatePools(storageosCli, []string{name}, mergePoolUpdate(flags), success)
+}
+
+func updatePools(storageosCli *command.StorageOSCli, names []string, mergePool func(pool *types.Pool) error, success func(name string)) error {
+	client := storageosCli.Client()
+	ctx := context.Background()
+
+	for _, name := range names {
+		pool, err := client.Pool(name)
+		if err != nil {
+			return err
+		}
+
+		before := append([]string(nil), pool.ControllerNames...)
+		err = mergePool(pool)
+		if err != nil {
+			return err
+		}
+		if warning := capacityWarning(pool, removedNames(before, pool.ControllerNames)); warning != "" {
+			fmt.Fprintf(storageosCli.Err(), "WARNING: %s\n", warning)
+		}
+
+		params := types.PoolCreateOptions{
+			Name:            pool.Name,
+			Description:     pool.Description,
+			Default:         pool.Default,
+			DefaultDriver:   pool.DefaultDriver,
+			ControllerNames: pool.ControllerNames,
+			DriverNames:     pool.DriverNames,
+			Active:          pool.Active,
+			Labels:          pool.Labels,
+			Context:         ctx,
+		}
+		_, err = storageosCli.Client().PoolUpdate(params)
+		if err != nil {
+			return err
+		}
+		success(name)
+	}
+	return nil
+}
+
+func mergePoolUpdate(flags *pflag.FlagSet) func(*types.Pool) error {
+	return func(pool *types.Pool) error {
+		var err error
+		pool.ControllerNames, err = mergeNames(flags, flagControllerAdd, flagControllerRemove, pool.ControllerNames, "controller")
+		if err != nil {
+			return err
+		}
+		pool.DriverNames, err = mergeNames(flags, flagDriverAdd, flagDriverRemove, pool.DriverNames, "driver")
+		if err != nil {
+			return err
+		}
+		if flags.Changed(flagDefault) {
+			b, err := flags.GetBool(flagDefault)
+			if err != nil {
+				return err
+			}
+			pool.Default = b
+		}
+		if flags.Changed(flagActive) {
+			b, err := flags.GetBool(flagActive)
+			if err != nil {
+				return err
+			}
+			pool.Active = b
+		}
+		if pool.Labels == nil {
+			pool.Labels = make(map[string]string)
+		}
+		if flags.Changed(flagLabelAdd) {
+			labels := flags.Lookup(flagLabelAdd).Value.(*opts.ListOpts).GetAll()
+			for k, v := range opts.ConvertKVStringsToMap(labels) {
+				pool.Labels[k] = v
+			}
+		}
+		if flags.Changed(flagLabelRemove) {
+			keys := flags.Lookup(flagLabelRemove).Value.(*opts.ListOpts).GetAll()
+			for _, k := range keys {
+				// if a key doesn't exist, fail the command explicitly
+				if _, exists := pool.Labels[k]; !exists {
+					return fmt.Errorf("key %s doesn't exist in pool's labels", k)
+				}
+				delete(pool.Labels, k)
+			}
+		}
+		return nil
+	}
+}
+
+// mergeNames adds the names given with the add flag to names, and removes
+// those given with the remove flag, returning them sorted. Removing a name
+// that isn't in names is an error.
+func mergeNames(flags *pflag.FlagSet, add, remove string, names []string, kind string) ([]string, error) {
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		set[n] = true
+	}
+	if flags.Changed(add) {
+		for _, n := range flags.Lookup(add).Value.(*opts.ListOpts).GetAll() {
+			set[n] = true
+		}
+	}
+	if flags.Changed(remove) {
+		for _, n := range flags.Lookup(remove).Value.(*opts.ListOpts).GetAll() {
+			if !set[n] {
+				return nil, fmt.Errorf("%s %s isn't in the pool", kind, n)
+			}
+			delete(set, n)
+		}
+	}
+
+	merged := make([]string, 0, len(set))
+	for n := range set {
+		merged = append(merged, n)
+	}
+	sort.Strings(merged)
+	return merged, nil
+}
+
+// removedNames returns the names in before that aren't in after.
+func removedNames(before, after []string) []string {
+	kept := make(map[string]bool, len(after))
+	for _, n := range after {
+		kept[n] = true
+	}
+	var removed []string
+	for _, n := range before {
+		if !kept[n] {
+			removed = append(removed, n)
+		}
+	}
+	return removed
+}
+
+// capacityWarning returns a warning if the capacity provisioned on the
+// removed controllers doesn't fit in the capacity left available on the
+// rest of the pool, or "" if it does.
+func capacityWarning(pool *types.Pool, removed []string) string {
+	if len(removed) == 0 {
+		return ""
+	}
+	isRemoved := make(map[string]bool, len(removed))
+	for _, n := range removed {
+		isRemoved[n] = true
+	}
+
+	var available, provisioned uint64
+	for _, inst := range pool.DriverInstances {
+		if inst == nil || !isRemoved[inst.ControllerName] {
+			continue
+		}
+		available += inst.CapacityStats.AvailableCapacityBytes
+		provisioned += inst.CapacityStats.ProvisionedCapacityBytes
+	}
+
+	remaining := uint64(0)
+	if pool.CapacityStats.AvailableCapacityBytes > available {
+		remaining = pool.CapacityStats.AvailableCapacityBytes - available
+	}
+	if remaining >= provisioned {
+		return ""
+	}
+	return fmt.Sprintf("pool %s would have %s available on its remaining controllers, which doesn't cover the %s provisioned on the removed ones",
+		pool.Name, units.BytesSize(float64(remaining)), units.BytesSize(float64(provisioned)))
+}
+
+var updateDescription = `
+Update a capacity pool. Controllers, drivers and labels are added and
+removed with the -add and -rm flags, and are left as they are otherwise.
+
+Removing a controller warns when the capacity provisioned on it doesn't fit
+in the capacity left available on the rest of the pool.
+`
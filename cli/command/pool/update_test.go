@@ -0,0 +1,76 @@
+package pool
+
+import (
+	"testing"
+
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+func testPool() *types.Pool {
+	instance := func(controller string, available, provisioned uint64) *types.DriverInstance {
+		return &types.DriverInstance{
+			ControllerName: controller,
+			CapacityStats: types.CapacityStats{
+				AvailableCapacityBytes:   available,
+				ProvisionedCapacityBytes: provisioned,
+			},
+		}
+	}
+	return &types.Pool{
+		Name:            "fast",
+		ControllerNames: []string{"a", "b", "c"},
+		DriverNames:     []string{"filesystem"},
+		DriverInstances: []*types.DriverInstance{
+			instance("a", 100, 50),
+			instance("b", 20, 150),
+			instance("c", 30, 10),
+		},
+		CapacityStats: types.CapacityStats{AvailableCapacityBytes: 150},
+	}
+}
+
+func TestMergePoolUpdate(t *testing.T) {
+	cmd := newUpdateCommand(nil)
+	flags := cmd.Flags()
+	assert.NilError(t, flags.Parse([]string{
+		"--controller-add", "d", "--controller-rm", "b",
+		"--driver-add", "ssd",
+		"--active=false",
+		"--label-add", "tier=1",
+	}))
+
+	pool := testPool()
+	assert.NilError(t, mergePoolUpdate(flags)(pool))
+	assert.DeepEqual(t, pool.ControllerNames, []string{"a", "c", "d"})
+	assert.DeepEqual(t, pool.DriverNames, []string{"filesystem", "ssd"})
+	assert.Equal(t, pool.Active, false)
+	assert.Equal(t, pool.Default, false)
+	assert.DeepEqual(t, pool.Labels, map[string]string{"tier": "1"})
+}
+
+func TestMergePoolUpdateRemoveMissing(t *testing.T) {
+	cmd := newUpdateCommand(nil)
+	flags := cmd.Flags()
+	assert.NilError(t, flags.Parse([]string{"--driver-rm", "ssd"}))
+
+	err := mergePoolUpdate(flags)(testPool())
+	assert.Error(t, err, "driver ssd isn't in the pool")
+}
+
+func TestCapacityWarning(t *testing.T) {
+	pool := testPool()
+
+	// Removing a only drops its 100 bytes available, leaving 50 for its 50
+	// provisioned.
+	assert.Equal(t, capacityWarning(pool, []string{"a"}), "")
+	assert.Equal(t, capacityWarning(pool, nil), "")
+
+	// Removing b leaves 130 available for its 150 provisioned.
+	assert.Contains(t, capacityWarning(pool, []string{"b"}), "doesn't cover the 150B provisioned")
+}
+
+func TestRemovedNames(t *testing.T) {
+	assert.DeepEqual(t, removedNames([]string{"a", "b", "c"}, []string{"a", "c", "d"}), []string{"b"})
+	assert.Equal(t, len(removedNames([]string{"a"}, []string{"a", "b"})), 0)
+}
@@ -21,6 +21,7 @@ func NewRuleCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 		command.WithAlias(newListCommand(storageosCli), command.ListAliases...),
 		command.WithAlias(newUpdateCommand(storageosCli), command.UpdateAliases...),
 		command.WithAlias(newRemoveCommand(storageosCli), command.RemoveAliases...),
+		newTestCommand(storageosCli),
 	)
 	return cmd
 }
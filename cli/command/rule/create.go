@@ -10,6 +10,7 @@ import (
 	"github.com/storageos/go-cli/cli"
 	"github.com/storageos/go-cli/cli/command"
 	"github.com/storageos/go-cli/cli/opts"
+	"github.com/storageos/go-cli/pkg/selector"
 )
 
 type createOptions struct {
@@ -50,9 +51,9 @@ storageos rule create --selector env==prod --action add --label storageos.featur
 	flags.Lookup("name").Hidden = true
 	flags.StringVarP(&opt.description, "description", "d", "", "Rule description")
 	flags.StringVarP(&opt.ruleAction, "action", "a", "add", "Rule action (add|remove)")
-	flags.StringVarP(&opt.selector, "selector", "s", "", "selectors to trigger rule, i.e. 'environment = production' (operators !|=|==|in|!=|notin|exists|<|>")
+	flags.StringVarP(&opt.selector, "selector", "s", "", "Selector of the volumes the rule applies to, e.g. 'env==prod,tier in (db,cache)' (operators !|=|==|!=|in|notin|gt|lt)")
 	flags.IntVarP(&opt.weight, "weight", "w", 5, "Rule weight determines processing order (0-10)")
-	flags.StringVarP(&opt.namespace, "namespace", "n", "default", "Rule namespace")
+	flags.StringVarP(&opt.namespace, "namespace", "n", "", `Rule namespace (default "default")`)
 	flags.BoolVar(&opt.active, "active", true, "Enable or disable the rule")
 
 	flags.Var(&opt.labels, "label", "Labels to apply when rule is triggered")
@@ -64,9 +65,16 @@ func runCreate(storageosCli *command.StorageOSCli, opt createOptions) error {
 	if _, err := opts.ValidateRuleAction(opt.ruleAction); err != nil {
 		return err
 	}
+	if _, err := selector.Parse(opt.selector); err != nil {
+		return err
+	}
 
 	client := storageosCli.Client()
 
+	if opt.namespace == "" {
+		opt.namespace = storageosCli.Namespace()
+	}
+
 	params := types.RuleCreateOptions{
 		Name:        opt.name,
 		Namespace:   opt.namespace,
@@ -4,12 +4,14 @@ import (
 	"github.com/dnephin/cobra"
 	"github.com/storageos/go-cli/cli"
 	"github.com/storageos/go-cli/cli/command"
+	"github.com/storageos/go-cli/cli/command/formatter"
 	"github.com/storageos/go-cli/cli/command/inspect"
 	"github.com/storageos/go-cli/pkg/validation"
 )
 
 type inspectOptions struct {
 	format string
+	output string
 	names  []string
 }
 
@@ -26,7 +28,9 @@ func newInspectCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringVarP(&opt.format, "format", "f", "", "Format the output using the given Go template")
+	flags := cmd.Flags()
+	flags.StringVarP(&opt.format, "format", "f", "", "Format the output using the given Go template")
+	flags.StringVarP(&opt.output, "output", "o", "", formatter.OutputUsage)
 
 	return cmd
 }
@@ -35,7 +39,7 @@ func runInspect(storageosCli *command.StorageOSCli, opt inspectOptions) error {
 	client := storageosCli.Client()
 
 	getFunc := func(ref string) (interface{}, []byte, error) {
-		namespace, name, err := validation.ParseRefWithDefault(ref)
+		namespace, name, err := validation.ParseRefInNamespace(ref, storageosCli.Namespace())
 		if err != nil {
 			return nil, nil, err
 		}
@@ -43,5 +47,9 @@ func runInspect(storageosCli *command.StorageOSCli, opt inspectOptions) error {
 		return i, nil, err
 	}
 
+	if opt.output != "" {
+		return inspect.Output(storageosCli.Out(), opt.names, opt.output, opt.format, formatter.RuleKind, getFunc)
+	}
+
 	return inspect.Inspect(storageosCli.Out(), opt.names, opt.format, getFunc)
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/storageos/go-cli/cli"
 	"github.com/storageos/go-cli/cli/command"
 	"github.com/storageos/go-cli/cli/command/formatter"
+	"github.com/storageos/go-cli/pkg/selector"
 )
 
 type byRuleName []*types.Rule
@@ -21,6 +22,7 @@ func (r byRuleName) Less(i, j int) bool {
 type listOptions struct {
 	quiet     bool
 	format    string
+	output    string
 	selector  string
 	namespace string
 }
@@ -41,6 +43,7 @@ func newListCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&opt.quiet, "quiet", "q", false, "Only display rule names")
 	flags.StringVar(&opt.format, "format", "", "Pretty-print rules using a Go template")
+	flags.StringVarP(&opt.output, "output", "o", "", formatter.OutputUsage)
 	flags.StringVarP(&opt.selector, "selector", "s", "", "Provide selector (e.g. to list all rules with label app=cassandra ' --selector=app=cassandra')")
 	flags.StringVarP(&opt.namespace, "namespace", "n", "", "Namespace scope")
 
@@ -48,6 +51,16 @@ func newListCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 }
 
 func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
+	output, err := formatter.ParseOutput(opt.output, opt.format, opt.quiet)
+	if err != nil {
+		return err
+	}
+
+	sel, err := selector.Parse(opt.selector)
+	if err != nil {
+		return err
+	}
+
 	client := storageosCli.Client()
 
 	params := types.ListOptions{
@@ -60,6 +73,15 @@ func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
 	if err != nil {
 		return err
 	}
+	if !sel.Empty() {
+		matched := rules[:0]
+		for _, rule := range rules {
+			if sel.Matches(rule.Labels) {
+				matched = append(matched, rule)
+			}
+		}
+		rules = matched
+	}
 
 	format := opt.format
 	if len(format) == 0 {
@@ -69,9 +91,16 @@ func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
 			format = formatter.TableFormatKey
 		}
 	}
+	if output.IsWide() {
+		format = formatter.WideFormatKey
+	}
 
 	sort.Sort(byRuleName(rules))
 
+	if output.IsStructured() {
+		return formatter.WriteOutput(storageosCli.Out(), output, formatter.RuleKind, rules)
+	}
+
 	ruleCtx := formatter.Context{
 		Output: storageosCli.Out(),
 		Format: formatter.NewRuleFormat(format, opt.quiet),
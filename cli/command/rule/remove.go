@@ -43,7 +43,7 @@ func runRemove(storageosCli *command.StorageOSCli, opt *removeOptions) error {
 	status := 0
 
 	for _, ref := range opt.rules {
-		namespace, name, err := validation.ParseRefWithDefault(ref)
+		namespace, name, err := validation.ParseRefInNamespace(ref, storageosCli.Namespace())
 		if err != nil {
 			fmt.Fprintf(storageosCli.Err(), "%s\n", err)
 			status = 1
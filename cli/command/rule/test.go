@@ -0,0 +1,195 @@
+package rule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dnephin/cobra"
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+	"github.com/storageos/go-cli/cli/command/formatter"
+	"github.com/storageos/go-cli/cli/opts"
+	"github.com/storageos/go-cli/pkg/selector"
+	"github.com/storageos/go-cli/pkg/validation"
+)
+
+type testOptions struct {
+	quiet      bool
+	format     string
+	ref        string
+	namespace  string
+	selector   string
+	ruleAction string
+	weight     int
+	labels     opts.ListOpts
+}
+
+func newTestCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	opt := testOptions{
+		labels: opts.NewListOpts(opts.ValidateEnv),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "test [OPTIONS] [RULE]",
+		Short: "Show the volumes a rule matches, and the labels they would end up with",
+		Long:  testDescription,
+		Args:  cli.RequiresMaxArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case len(args) == 1 && cmd.Flags().Changed("selector"):
+				return errors.New("Conflicting options: either specify --selector or provide a rule, not both")
+			case len(args) == 1:
+				opt.ref = args[0]
+			case !cmd.Flags().Changed("selector"):
+				return fmt.Errorf("Please provide a rule, or a --selector to test")
+			}
+			return runTest(storageosCli, opt)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opt.quiet, "quiet", "q", false, "Only display volume names")
+	flags.StringVar(&opt.format, "format", "", "Pretty-print matches using a Go template")
+	flags.StringVarP(&opt.namespace, "namespace", "n", "", `Namespace of the rule (default "default")`)
+	flags.StringVarP(&opt.selector, "selector", "s", "", "Selector to test instead of an existing rule, e.g. 'env==prod'")
+	flags.StringVarP(&opt.ruleAction, "action", "a", "add", "Action of the tested selector (add|remove)")
+	flags.IntVarP(&opt.weight, "weight", "w", 5, "Weight of the tested selector (0-10)")
+	flags.Var(&opt.labels, "label", "Labels the tested selector adds or removes")
+
+	return cmd
+}
+
+func runTest(storageosCli *command.StorageOSCli, opt testOptions) error {
+	client := storageosCli.Client()
+
+	namespace := opt.namespace
+	if namespace == "" {
+		namespace = storageosCli.Namespace()
+	}
+
+	var rule *types.Rule
+	if opt.ref != "" {
+		ref := opt.ref
+		if opt.namespace != "" && !strings.Contains(ref, "/") {
+			ref = opt.namespace + "/" + ref
+		}
+		ns, name, err := validation.ParseRefInNamespace(ref, storageosCli.Namespace())
+		if err != nil {
+			return err
+		}
+		if rule, err = client.Rule(ns, name); err != nil {
+			return err
+		}
+		namespace = rule.Namespace
+	} else {
+		if _, err := opts.ValidateRuleAction(opt.ruleAction); err != nil {
+			return err
+		}
+		rule = &types.Rule{
+			Namespace:  namespace,
+			Selector:   opt.selector,
+			RuleAction: opt.ruleAction,
+			Weight:     opt.weight,
+			Labels:     opts.ConvertKVStringsToMap(opt.labels.GetAll()),
+		}
+	}
+
+	sel, err := selector.Parse(rule.Selector)
+	if err != nil {
+		return err
+	}
+
+	// The tested rule replaces the live rule of the same name, and is
+	// applied even if it isn't active.
+	list, err := client.RuleList(types.ListOptions{Namespace: namespace})
+	if err != nil {
+		return err
+	}
+	rules := []*types.Rule{rule}
+	for _, r := range list {
+		if r.Active && (rule.Name == "" || r.Name != rule.Name) {
+			rules = append(rules, r)
+		}
+	}
+
+	volumes, err := client.VolumeList(types.ListOptions{Namespace: namespace})
+	if err != nil {
+		return err
+	}
+	sort.Slice(volumes, func(i, j int) bool {
+		return volumes[i].Name < volumes[j].Name
+	})
+
+	var matches []formatter.RuleMatch
+	for _, vol := range volumes {
+		if !sel.Matches(vol.Labels) {
+			continue
+		}
+		after, err := applyRules(vol.Labels, rules)
+		if err != nil {
+			return err
+		}
+		matches = append(matches, formatter.RuleMatch{Volume: vol, Before: vol.Labels, After: after})
+	}
+
+	format := opt.format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
+
+	matchCtx := formatter.Context{
+		Output: storageosCli.Out(),
+		Format: formatter.NewRuleMatchFormat(format, opt.quiet),
+	}
+	return formatter.RuleMatchWrite(matchCtx, matches)
+}
+
+// applyRules returns the labels a volume with the given labels ends up
+// with once the rules are applied. Rules whose selector matches the labels
+// are applied in order of weight, lowest first and then by name, so that
+// rules of greater weight take precedence.
+func applyRules(labels map[string]string, rules []*types.Rule) (map[string]string, error) {
+	sorted := append([]*types.Rule(nil), rules...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Weight != sorted[j].Weight {
+			return sorted[i].Weight < sorted[j].Weight
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	result := make(map[string]string, len(labels))
+	for k, v := range labels {
+		result[k] = v
+	}
+
+	for _, rule := range sorted {
+		sel, err := selector.Parse(rule.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s/%s: %v", rule.Namespace, rule.Name, err)
+		}
+		if !sel.Matches(labels) {
+			continue
+		}
+		for k, v := range rule.Labels {
+			if rule.RuleAction == "remove" {
+				delete(result, k)
+				continue
+			}
+			result[k] = v
+		}
+	}
+	return result, nil
+}
+
+var testDescription = `
+Show the volumes a rule matches, with the labels they have and the labels
+they would end up with once all the active rules of the namespace are
+applied. Test an existing rule by name, or a new one with --selector,
+--action, --weight and --label.
+
+Rules are matched against the labels volumes have now, and applied in order
+of weight, lowest first, so that rules of greater weight take precedence.
+`
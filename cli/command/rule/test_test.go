@@ -0,0 +1,28 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+func TestApplyRules(t *testing.T) {
+	rules := []*types.Rule{
+		{Name: "triple", Weight: 8, RuleAction: "add", Selector: "tier==db", Labels: map[string]string{"storageos.feature.replicas": "3"}},
+		{Name: "replicate", Weight: 5, RuleAction: "add", Selector: "env==prod", Labels: map[string]string{"storageos.feature.replicas": "2", "backup": "daily"}},
+		{Name: "nobackup", Weight: 5, RuleAction: "remove", Selector: "!backup", Labels: map[string]string{"backup": ""}},
+	}
+
+	// The heavier rule wins, and rules of equal weight apply by name.
+	after, err := applyRules(map[string]string{"env": "prod", "tier": "db"}, rules)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, after, map[string]string{"env": "prod", "tier": "db", "backup": "daily", "storageos.feature.replicas": "3"})
+
+	after, err = applyRules(map[string]string{"env": "prod", "backup": "weekly"}, rules)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, after, map[string]string{"env": "prod", "backup": "daily", "storageos.feature.replicas": "2"})
+
+	_, err = applyRules(nil, []*types.Rule{{Name: "bad", Namespace: "default", Selector: "env in prod"}})
+	assert.Error(t, err, `rule default/bad: invalid selector`)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/storageos/go-cli/cli"
 	"github.com/storageos/go-cli/cli/command"
 	"github.com/storageos/go-cli/cli/opts"
+	"github.com/storageos/go-cli/pkg/selector"
 	"github.com/storageos/go-cli/pkg/validation"
 )
 
@@ -54,7 +55,7 @@ func newUpdateCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 	flags.StringVarP(&opt.description, flagDescription, "d", "", `Rule description`)
 	flags.StringVarP(&opt.ruleAction, flagRuleAction, "a", "add", "Rule action (add|remove)")
 	flags.StringVarP(&opt.operator, flagOperator, "o", "==", "Comparison operator (!|=|==|in|!=|notin|exists|gt|lt)")
-	flags.StringVarP(&opt.selector, flagSelector, "s", "", "Rule selector (key==value)")
+	flags.StringVarP(&opt.selector, flagSelector, "s", "", "Rule selector, e.g. 'env==prod,tier in (db,cache)'")
 	flags.IntVarP(&opt.weight, flagWeight, "w", 5, "Rule weight determines processing order (0-10)")
 	flags.BoolVar(&opt.active, flagActive, true, "Enable or disable the pool")
 	flags.Var(&opt.labels, flagLabelAdd, "Add or update a label (key=value)")
@@ -64,6 +65,16 @@ func newUpdateCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 }
 
 func runUpdate(storageosCli *command.StorageOSCli, flags *pflag.FlagSet, ref string) error {
+	if flags.Changed(flagSelector) {
+		str, err := flags.GetString(flagSelector)
+		if err != nil {
+			return err
+		}
+		if _, err := selector.Parse(str); err != nil {
+			return err
+		}
+	}
+
 	success := func(_ string) {
 		fmt.Fprintln(storageosCli.Out(), ref)
 	}
@@ -76,7 +87,7 @@ func updateRules(storageosCli *command.StorageOSCli, refs []string, mergeRule fu
 
 	for _, ref := range refs {
 
-		namespace, name, err := validation.ParseRefWithDefault(ref)
+		namespace, name, err := validation.ParseRefInNamespace(ref, storageosCli.Namespace())
 		if err != nil {
 			return err
 		}
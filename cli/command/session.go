@@ -0,0 +1,195 @@
+package command
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	api "github.com/storageos/go-api"
+	"github.com/storageos/go-cli/cli/config/configfile"
+)
+
+const (
+	// tokenExpiryMargin is how long before its expiry a session token is
+	// refreshed, so that it doesn't expire during a command.
+	tokenExpiryMargin = 30 * time.Second
+
+	// loginTimeout bounds getting a new session token, so that an
+	// unreachable host doesn't hold up a command until the OS gives up.
+	loginTimeout = 10 * time.Second
+)
+
+// NewSessionToken gets a session token for the credentials set on client,
+// returning it with its expiry.
+func NewSessionToken(client *api.Client) (string, time.Time, error) {
+	token, err := client.Login()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, tokenExpiry(token), nil
+}
+
+// tokenExpiry returns the expiry of a JWT token, or the zero time if it
+// can't be found.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
+
+// useSession authenticates client with the session token cached by login
+// for host. Nothing is sent until the client's first request, which gets a
+// new token with the stored password if the cached one is missing or has
+// expired, and falls back to basic authentication if that fails. It returns
+// false when the cached token can't be read, leaving the client to use
+// basic authentication.
+func useSession(client *api.Client, version string, configFile *configfile.ConfigFile, host, username, password string) bool {
+	token, expires, err := configFile.CredentialsStore.GetToken(host)
+	if err != nil && err != configfile.ErrNotFound {
+		return false
+	}
+
+	// The password is only ever sent by a client of its own, as it can't
+	// be unset once set.
+	login, err := NewVersionedClient(currentEndpoint(client), version, client.TLSConfig)
+	if err != nil {
+		return false
+	}
+	login.HTTPClient.Transport = client.HTTPClient.Transport
+	login.SetTimeout(loginTimeout)
+	login.SetAuth(username, password)
+
+	s := &session{
+		login:      login,
+		configFile: configFile,
+		host:       host,
+		username:   username,
+		password:   password,
+		token:      token,
+		expires:    expires,
+	}
+
+	client.HTTPClient.Transport = &sessionTransport{
+		next:    client.HTTPClient.Transport,
+		session: s,
+	}
+	return true
+}
+
+// sessionToken returns the session token used by client, getting one if
+// needed, or "" if it has none.
+func sessionToken(client *api.Client) string {
+	if t, ok := client.HTTPClient.Transport.(*sessionTransport); ok {
+		return t.session.current()
+	}
+	return ""
+}
+
+// session holds the token sent by a client, and what's needed to renew it.
+type session struct {
+	login      *api.Client
+	configFile *configfile.ConfigFile
+	host       string
+	username   string
+	password   string
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// current returns the session token, getting a new one if it's missing or
+// about to expire, or "" if a new one couldn't be had.
+func (s *session) current() string {
+	s.mu.Lock()
+	token, expires := s.token, s.expires
+	s.mu.Unlock()
+
+	if token != "" && (expires.IsZero() || time.Now().Add(tokenExpiryMargin).Before(expires)) {
+		return token
+	}
+	token, err := s.refresh()
+	if err != nil {
+		return ""
+	}
+	return token
+}
+
+// refresh logs in again and caches the new token. Failing to save it only
+// means the next command logs in again.
+func (s *session) refresh() (string, error) {
+	token, expires, err := NewSessionToken(s.login)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	s.token = token
+	s.expires = expires
+	s.mu.Unlock()
+
+	if err := s.configFile.CredentialsStore.SetToken(s.host, token, expires); err == nil {
+		s.configFile.Save()
+	}
+	return token, nil
+}
+
+// sessionTransport sends the session token as a bearer token, and gets a
+// new one when the API rejects it. Without a token it sends the stored
+// credentials instead.
+type sessionTransport struct {
+	next    http.RoundTripper
+	session *session
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *sessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(t.session.authorize(req, t.session.current()))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	token, err := t.session.refresh()
+	if err != nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	r := t.session.authorize(req, token)
+	if req.Body != nil {
+		if r.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return t.next.RoundTrip(r)
+}
+
+// authorize returns a copy of req authenticated with token, or with the
+// stored credentials if token is "".
+func (s *session) authorize(req *http.Request, token string) *http.Request {
+	r := req.Clone(req.Context())
+	if token == "" {
+		r.SetBasicAuth(s.username, s.password)
+		return r
+	}
+	r.Header.Set("Authorization", "Bearer "+token)
+	return r
+}
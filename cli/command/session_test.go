@@ -0,0 +1,149 @@
+package command
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/storageos/go-cli/cli/config/configfile"
+	cliflags "github.com/storageos/go-cli/cli/flags"
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+func testJWT(exp time.Time) string {
+	enc := base64.RawURLEncoding.EncodeToString
+	return enc([]byte(`{"alg":"HS256"}`)) + "." + enc([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix()))) + ".sig"
+}
+
+func TestTokenExpiry(t *testing.T) {
+	exp := time.Unix(1700000000, 0)
+	assert.Equal(t, tokenExpiry(testJWT(exp)).Equal(exp), true)
+	assert.Equal(t, tokenExpiry("opaque").IsZero(), true)
+	assert.Equal(t, tokenExpiry("a.!!!.c").IsZero(), true)
+}
+
+// authServer is an API server handing out tokens for admin/secret, and
+// only accepting the last one given.
+type authServer struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	token  string
+	logins int
+	basic  int
+}
+
+func newAuthServer() *authServer {
+	s := &authServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		switch {
+		case r.URL.Path == "/version":
+			fmt.Fprint(w, `{"apiVersion": "1"}`)
+		case strings.HasSuffix(r.URL.Path, "/auth/login"):
+			if u, p, ok := r.BasicAuth(); !ok || u != "admin" || p != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			s.logins++
+			s.token = testJWT(time.Now().Add(time.Hour))
+			fmt.Fprintf(w, `{"token": %q}`, s.token)
+		case r.Header.Get("Authorization") == "Bearer "+s.token:
+			w.WriteHeader(http.StatusOK)
+		default:
+			if _, _, ok := r.BasicAuth(); ok {
+				s.basic++
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	return s
+}
+
+func (s *authServer) counts() (logins, basic int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logins, s.basic
+}
+
+func (s *authServer) revoke() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = "revoked"
+}
+
+func TestSession(t *testing.T) {
+	srv := newAuthServer()
+	defer srv.Close()
+
+	host := strings.TrimPrefix(srv.URL, "http://")
+	opt := &cliflags.CommonOptions{Hosts: []string{"tcp://" + host}}
+
+	configFile := &configfile.ConfigFile{CredentialsStore: make(configfile.CredStore)}
+	assert.NilError(t, configFile.SetCredentials(host, "admin", "secret"))
+
+	// Logged in before sessions existed: a token is got on the first
+	// request and cached.
+	client, err := NewAPIClientFromFlags(opt, configFile)
+	assert.NilError(t, err)
+	logins, _ := srv.counts()
+	assert.Equal(t, logins, 0)
+	assert.NilError(t, client.Ping())
+	token, _, err := configFile.CredentialsStore.GetToken(host)
+	assert.NilError(t, err)
+	assert.Equal(t, sessionToken(client), token)
+
+	// The cached token is reused.
+	client, err = NewAPIClientFromFlags(opt, configFile)
+	assert.NilError(t, err)
+	assert.NilError(t, client.Ping())
+	logins, basic := srv.counts()
+	assert.Equal(t, logins, 1)
+	assert.Equal(t, basic, 0)
+
+	// Expired tokens are replaced before use.
+	assert.NilError(t, configFile.CredentialsStore.SetToken(host, token, time.Now().Add(-time.Minute)))
+	client, err = NewAPIClientFromFlags(opt, configFile)
+	assert.NilError(t, err)
+	logins, _ = srv.counts()
+	assert.Equal(t, logins, 1)
+	assert.NilError(t, client.Ping())
+	logins, _ = srv.counts()
+	assert.Equal(t, logins, 2)
+
+	// Tokens rejected by the API are replaced and the request retried.
+	srv.revoke()
+	assert.NilError(t, client.Ping())
+	logins, basic = srv.counts()
+	assert.Equal(t, logins, 3)
+	assert.Equal(t, basic, 0)
+
+	// Other credentials than the stored ones use basic authentication.
+	opt.Username, opt.Password = "other", "pass"
+	client, err = NewAPIClientFromFlags(opt, configFile)
+	assert.NilError(t, err)
+	assert.Equal(t, sessionToken(client), "")
+	assert.Error(t, client.Ping(), "")
+	_, basic = srv.counts()
+	assert.Equal(t, basic, 1)
+}
+
+func TestSessionUnreachableHost(t *testing.T) {
+	host := "127.0.0.1:1"
+	opt := &cliflags.CommonOptions{Hosts: []string{"tcp://" + host}}
+
+	configFile := &configfile.ConfigFile{CredentialsStore: make(configfile.CredStore)}
+	assert.NilError(t, configFile.SetCredentials(host, "admin", "secret"))
+
+	// Without a cached token, nothing is sent until the first request.
+	client, err := NewAPIClientFromFlags(opt, configFile)
+	assert.NilError(t, err)
+	assert.Error(t, client.Ping(), "")
+}
@@ -1,131 +1,242 @@
 package system
 
-// import (
-// 	"fmt"
-// 	"io"
-// 	"io/ioutil"
-// 	"text/template"
-// 	"time"
-//
-// 	"context"
-//
-// 	"github.com/dnephin/cobra"
-// 	"github.com/storageos/go-api/types"
-// 	"github.com/storageos/go-cli/cli"
-// 	"github.com/storageos/go-cli/cli/command"
-// 	"github.com/storageos/go-cli/pkg/jsonlog"
-// 	"github.com/storageos/go-cli/pkg/templates"
-// )
-//
-// type eventsOptions struct {
-// 	since  string
-// 	until  string
-// 	format string
-// }
-//
-// // NewEventsCommand creates a new cobra.Command for `docker events`
-// func NewEventsCommand(storageosCli *command.StorageOSCli) *cobra.Command {
-// 	opt := eventsOptions{}
-//
-// 	cmd := &cobra.Command{
-// 		Use:   "events [OPTIONS]",
-// 		Short: "Get real time events from the server",
-// 		Args:  cli.NoArgs,
-// 		RunE: func(cmd *cobra.Command, args []string) error {
-// 			return runEvents(storageosCli, &opt)
-// 		},
-// 	}
-//
-// 	flags := cmd.Flags()
-// 	flags.StringVar(&opt.since, "since", "", "Show all events created since timestamp")
-// 	flags.StringVar(&opt.until, "until", "", "Stream events until this timestamp")
-// 	flags.StringVar(&opt.format, "format", "", "Format the output using the given Go template")
-//
-// 	return cmd
-// }
-//
-// func runEvents(storageosCli *command.StorageOSCli, opt *eventsOptions) error {
-// 	tmpl, err := makeTemplate(opt.format)
-// 	if err != nil {
-// 		return cli.StatusError{
-// 			StatusCode: 64,
-// 			Status:     "Error parsing format: " + err.Error()}
-// 	}
-// 	// options := types.EventsOptions{
-// 	// 	Since:   opts.since,
-// 	// 	Until:   opts.until,
-// 	// 	Filters: opts.filter.Value(),
-// 	// }
-//
-// 	ctx, cancel := context.WithCancel(context.Background())
-// 	events, errs := storageosCli.Client().Events(ctx, types.ListOptions{})
-// 	defer cancel()
-//
-// 	out := storageosCli.Out()
-//
-// 	for {
-// 		select {
-// 		case event := <-events:
-// 			if err := handleEvent(out, event, tmpl); err != nil {
-// 				return err
-// 			}
-// 		case err := <-errs:
-// 			if err == io.EOF {
-// 				return nil
-// 			}
-// 			return err
-// 		}
-// 	}
-// }
-//
-// func handleEvent(out io.Writer, event types.Request, tmpl *template.Template) error {
-// 	if tmpl == nil {
-// 		return prettyPrintEvent(out, event)
-// 	}
-// 	return formatEvent(out, event, tmpl)
-// }
-//
-// func makeTemplate(format string) (*template.Template, error) {
-// 	if format == "" {
-// 		return nil, nil
-// 	}
-// 	tmpl, err := templates.Parse(format)
-// 	if err != nil {
-// 		return tmpl, err
-// 	}
-// 	// we execute the template for an empty message, so as to validate
-// 	// a bad template like "{{.badFieldString}}"
-// 	return tmpl, tmpl.Execute(ioutil.Discard, &types.Event{})
-// }
-//
-// // prettyPrintEvent prints all types of event information.
-// // Each output includes the event type, actor id, name and action.
-// // Actor attributes are printed at the end if the actor has any.
-// func prettyPrintEvent(out io.Writer, event types.Request) error {
-// 	if event.Timestamp != 0 {
-// 		fmt.Fprintf(out, "%s ", time.Unix(0, event.Timestamp).Format(jsonlog.RFC3339NanoFixed))
-// 	}
-//
-// 	fmt.Fprintf(out, "%s %s %s", event.EventType, event.Action, event.Message)
-//
-// 	// if len(event.Actor.Attributes) > 0 {
-// 	// 	var attrs []string
-// 	// 	var keys []string
-// 	// 	for k := range event.Actor.Attributes {
-// 	// 		keys = append(keys, k)
-// 	// 	}
-// 	// 	sort.Strings(keys)
-// 	// 	for _, k := range keys {
-// 	// 		v := event.Actor.Attributes[k]
-// 	// 		attrs = append(attrs, fmt.Sprintf("%s=%s", k, v))
-// 	// 	}
-// 	// 	fmt.Fprintf(out, " (%s)", strings.Join(attrs, ", "))
-// 	// }
-// 	fmt.Fprint(out, "\n")
-// 	return nil
-// }
-//
-// func formatEvent(out io.Writer, event types.Request, tmpl *template.Template) error {
-// 	defer out.Write([]byte{'\n'})
-// 	return tmpl.Execute(out, event)
-// }
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/dnephin/cobra"
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+	"github.com/storageos/go-cli/cli/command/formatter"
+	"github.com/storageos/go-cli/cli/opts"
+	"github.com/storageos/go-cli/pkg/templates"
+)
+
+const (
+	eventsJSONFormatKey = "json"
+
+	eventsTableRowFormat = "%-20s  %-10s  %-20s  %-36s  %-10s  %5s  %s\n"
+)
+
+// eventFilterKeys are the keys accepted by `events --filter`.
+var eventFilterKeys = []string{"action", "target", "status", "type"}
+
+type eventsOptions struct {
+	since  string
+	until  string
+	format string
+	filter opts.FilterOpt
+}
+
+// NewEventsCommand creates a new cobra.Command for `storageos events`
+func NewEventsCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	opt := eventsOptions{filter: opts.NewFilterOpt()}
+
+	cmd := &cobra.Command{
+		Use:     "events [OPTIONS]",
+		Short:   "Get real time events from the server",
+		Long:    eventsDescription,
+		Example: eventsExample,
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEvents(storageosCli, &opt)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.VarP(&opt.filter, "filter", "f", "Filter output based on conditions provided (action, target, status, type)")
+	flags.StringVar(&opt.since, "since", "", "Show all events created since timestamp")
+	flags.StringVar(&opt.until, "until", "", "Stream events until this timestamp")
+	flags.StringVar(&opt.format, "format", "", "Format the output: table (default), json or a Go template")
+
+	return cmd
+}
+
+func runEvents(storageosCli *command.StorageOSCli, opt *eventsOptions) error {
+	if err := opt.filter.Validate(eventFilterKeys...); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	since, err := opts.ParseTimestamp(opt.since, now)
+	if err != nil {
+		return err
+	}
+	until, err := opts.ParseTimestamp(opt.until, now)
+	if err != nil {
+		return err
+	}
+	if !since.IsZero() && !until.IsZero() && until.Before(since) {
+		return fmt.Errorf("--until (%s) must be after --since (%s)", opt.until, opt.since)
+	}
+
+	printer, err := newEventPrinter(storageosCli.Out(), opt.format)
+	if err != nil {
+		return cli.StatusError{
+			StatusCode: 64,
+			Status:     "Error parsing format: " + err.Error()}
+	}
+
+	inRange := func(event *types.Event) bool {
+		t := formatter.EventTime(event)
+		if !since.IsZero() && t.Before(since) {
+			return false
+		}
+		if !until.IsZero() && t.After(until) {
+			return false
+		}
+		return formatter.MatchEvent(opt.filter, event)
+	}
+
+	// Replay history first when the caller asks for past events.
+	if !since.IsZero() {
+		events, err := storageosCli.Client().EventList(types.ListOptions{})
+		if err != nil {
+			return err
+		}
+		sort.Sort(formatter.ByEventTime(events))
+		for _, event := range events {
+			if !inRange(event) {
+				continue
+			}
+			if err := printer.print(event); err != nil {
+				return err
+			}
+		}
+		if !until.IsZero() && !until.After(now) {
+			return nil
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if !until.IsZero() {
+		ctx, cancel = context.WithDeadline(ctx, until)
+		defer cancel()
+	}
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigc)
+	go func() {
+		select {
+		case <-sigc:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	events, errs := storageosCli.Events(ctx)
+	for {
+		select {
+		case request := <-events:
+			event := request.Event
+			if !inRange(&event) {
+				continue
+			}
+			if err := printer.print(&event); err != nil {
+				return err
+			}
+		case err := <-errs:
+			// Interrupts and reaching --until are the expected ways to end
+			// the stream.
+			if err == context.Canceled || err == context.DeadlineExceeded || err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// eventPrinter writes events to the output one at a time, as they arrive.
+type eventPrinter struct {
+	out           io.Writer
+	tmpl          *template.Template
+	json          bool
+	headerWritten bool
+}
+
+func newEventPrinter(out io.Writer, format string) (*eventPrinter, error) {
+	p := &eventPrinter{out: out}
+
+	switch format {
+	case "", formatter.TableFormatKey:
+		return p, nil
+	case eventsJSONFormatKey:
+		p.json = true
+		return p, nil
+	}
+
+	tmpl, err := templates.Parse(format)
+	if err != nil {
+		return nil, err
+	}
+	// we execute the template for an empty message, so as to validate
+	// a bad template like "{{.badFieldString}}"
+	if err := tmpl.Execute(ioutil.Discard, &types.Event{}); err != nil {
+		return nil, err
+	}
+	p.tmpl = tmpl
+	return p, nil
+}
+
+func (p *eventPrinter) print(event *types.Event) error {
+	switch {
+	case p.json:
+		b, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(p.out, "%s\n", b)
+		return err
+
+	case p.tmpl != nil:
+		if err := p.tmpl.Execute(p.out, event); err != nil {
+			return err
+		}
+		_, err := p.out.Write([]byte{'\n'})
+		return err
+	}
+
+	if !p.headerWritten {
+		fmt.Fprintf(p.out, eventsTableRowFormat, "TIME", "TYPE", "ACTION", "TARGET", "STATUS", "DONE", "MESSAGE")
+		p.headerWritten = true
+	}
+
+	_, err := fmt.Fprintf(p.out, eventsTableRowFormat,
+		formatter.EventTime(event).Local().Format("2006-01-02T15:04:05"),
+		event.EventType,
+		event.Action,
+		event.Target,
+		event.Status,
+		fmt.Sprintf("%d%%", event.ProgressPercent),
+		strings.TrimSpace(event.Message),
+	)
+	return err
+}
+
+var eventsDescription = `
+Stream events from the cluster as they happen, such as volume provisioning,
+mounts and failover. Use --since to replay past events before following the
+stream and --until to stop streaming at a given time. Interrupt the command
+to stop following the stream.
+`
+
+var eventsExample = `
+$ storageos events --filter action=create,status=failed
+$ storageos events --since 1h --until 10m
+$ storageos events --format json | jq .target
+$ storageos events --format '{{.Action}} {{.Target}} {{.Status}}'
+`
@@ -0,0 +1,25 @@
+package template
+
+import (
+	"github.com/dnephin/cobra"
+
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+)
+
+// NewTemplateCommand returns a cobra command for `template` subcommands
+func NewTemplateCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Manage name templates",
+		Args:  cli.NoArgs,
+		RunE:  storageosCli.ShowHelp,
+	}
+	cmd.AddCommand(
+		command.WithAlias(newCreateCommand(storageosCli), command.CreateAliases...),
+		command.WithAlias(newInspectCommand(storageosCli), command.InspectAliases...),
+		command.WithAlias(newListCommand(storageosCli), command.ListAliases...),
+		command.WithAlias(newRemoveCommand(storageosCli), command.RemoveAliases...),
+	)
+	return cmd
+}
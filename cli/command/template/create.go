@@ -0,0 +1,87 @@
+package template
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dnephin/cobra"
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+	"github.com/storageos/go-cli/cli/opts"
+)
+
+type createOptions struct {
+	name          string
+	description   string
+	format        string
+	autoIncrement bool
+	padding       bool
+	paddingLength int
+	active        bool
+	weight        int
+	objectTypes   opts.ListOpts
+	labels        opts.ListOpts
+}
+
+func newCreateCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	opt := createOptions{
+		objectTypes: opts.NewListOpts(nil),
+		labels:      opts.NewListOpts(opts.ValidateEnv),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "create [OPTIONS] [TEMPLATE]",
+		Short: "Create a name template",
+		Args:  cli.RequiresMaxArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				if opt.name != "" {
+					fmt.Fprint(storageosCli.Err(), "Conflicting options: either specify --name or provide positional arg, not both\n")
+					return cli.StatusError{StatusCode: 1}
+				}
+				opt.name = args[0]
+			}
+			return runCreate(storageosCli, opt)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opt.name, "name", "", "Template name")
+	flags.Lookup("name").Hidden = true
+	flags.StringVarP(&opt.description, "description", "d", "", "Template description")
+	flags.StringVar(&opt.format, "template", "", "Name format, matched against object labels (e.g. '{{.Labels.app}}-')")
+	flags.BoolVar(&opt.autoIncrement, "auto-increment", false, "Append an auto-incrementing number when the name is already taken")
+	flags.BoolVar(&opt.padding, "padding", false, "Zero-pad the auto-incrementing number")
+	flags.IntVar(&opt.paddingLength, "padding-length", 3, "Length of the padded number, ignored unless --padding is set")
+	flags.IntVarP(&opt.weight, "weight", "w", 5, "Template weight determines processing order (0-10)")
+	flags.BoolVar(&opt.active, "active", true, "Enable or disable the template")
+	flags.Var(&opt.objectTypes, "object-type", "Object types the template applies to (e.g. volume)")
+	flags.Var(&opt.labels, "label", "Labels an object must have for the template to be applied")
+
+	return cmd
+}
+
+func runCreate(storageosCli *command.StorageOSCli, opt createOptions) error {
+	client := storageosCli.Client()
+
+	params := types.TemplateCreateOptions{
+		Name:          opt.name,
+		Description:   opt.description,
+		Format:        opt.format,
+		AutoIncrement: opt.autoIncrement,
+		Padding:       opt.padding,
+		PaddingLength: opt.paddingLength,
+		Active:        opt.active,
+		Weight:        opt.weight,
+		ObjectTypes:   opt.objectTypes.GetAll(),
+		Labels:        opts.ConvertKVStringsToMap(opt.labels.GetAll()),
+		Context:       context.Background(),
+	}
+
+	if _, err := client.TemplateCreate(params); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(storageosCli.Out(), "%s\n", opt.name)
+	return nil
+}
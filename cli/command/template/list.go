@@ -0,0 +1,96 @@
+package template
+
+import (
+	"sort"
+
+	"github.com/dnephin/cobra"
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+	"github.com/storageos/go-cli/cli/command/formatter"
+	"github.com/storageos/go-cli/pkg/selector"
+)
+
+type byTemplateName []*types.Template
+
+func (r byTemplateName) Len() int      { return len(r) }
+func (r byTemplateName) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r byTemplateName) Less(i, j int) bool {
+	return r[i].Name < r[j].Name
+}
+
+type listOptions struct {
+	quiet    bool
+	format   string
+	selector string
+}
+
+func newListCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	opt := listOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List name templates",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(storageosCli, opt)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opt.quiet, "quiet", "q", false, "Only display template names")
+	flags.StringVar(&opt.format, "format", "", "Pretty-print templates using a Go template")
+	flags.StringVarP(&opt.selector, "selector", "s", "", "Provide selector (e.g. to list all templates with label app=cassandra ' --selector=app=cassandra')")
+
+	return cmd
+}
+
+func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
+	sel, err := selector.Parse(opt.selector)
+	if err != nil {
+		return err
+	}
+
+	client := storageosCli.Client()
+
+	params := types.ListOptions{
+		LabelSelector: opt.selector,
+	}
+
+	list, err := client.TemplateList(params)
+	if err != nil {
+		return err
+	}
+
+	templates := make([]*types.Template, len(list))
+	for i := range list {
+		templates[i] = &list[i]
+	}
+	if !sel.Empty() {
+		matched := templates[:0]
+		for _, tmpl := range templates {
+			if sel.Matches(tmpl.Labels) {
+				matched = append(matched, tmpl)
+			}
+		}
+		templates = matched
+	}
+
+	format := opt.format
+	if len(format) == 0 {
+		if len(storageosCli.ConfigFile().TemplatesFormat) > 0 && !opt.quiet {
+			format = storageosCli.ConfigFile().TemplatesFormat
+		} else {
+			format = formatter.TableFormatKey
+		}
+	}
+
+	sort.Sort(byTemplateName(templates))
+
+	templateCtx := formatter.Context{
+		Output: storageosCli.Out(),
+		Format: formatter.NewTemplateFormat(format, opt.quiet),
+	}
+	return formatter.TemplateWrite(templateCtx, templates)
+}
@@ -0,0 +1,60 @@
+package template
+
+import (
+	"fmt"
+
+	"github.com/dnephin/cobra"
+	"github.com/storageos/go-cli/cli"
+	"github.com/storageos/go-cli/cli/command"
+)
+
+type removeOptions struct {
+	templates []string
+}
+
+func newRemoveCommand(storageosCli *command.StorageOSCli) *cobra.Command {
+	var opt removeOptions
+
+	cmd := &cobra.Command{
+		Use:     "rm TEMPLATE [TEMPLATE...]",
+		Aliases: []string{"remove"},
+		Short:   "Remove one or more name templates",
+		Long:    removeDescription,
+		Example: removeExample,
+		Args:    cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opt.templates = args
+			return runRemove(storageosCli, &opt)
+		},
+	}
+
+	return cmd
+}
+
+func runRemove(storageosCli *command.StorageOSCli, opt *removeOptions) error {
+	client := storageosCli.Client()
+	status := 0
+
+	for _, name := range opt.templates {
+		if err := client.TemplateDelete(name); err != nil {
+			fmt.Fprintf(storageosCli.Err(), "%s\n", err)
+			status = 1
+			continue
+		}
+		fmt.Fprintf(storageosCli.Out(), "%s\n", name)
+	}
+
+	if status != 0 {
+		return cli.StatusError{StatusCode: status}
+	}
+	return nil
+}
+
+var removeDescription = `
+Remove one or more name templates. You cannot remove a template that is in use.
+`
+
+var removeExample = `
+$ storageos template rm testtemplate
+testtemplate
+`
@@ -0,0 +1,59 @@
+package command
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+
+	"github.com/docker/go-connections/tlsconfig"
+
+	api "github.com/storageos/go-api"
+	"github.com/storageos/go-cli/cli/config/configfile"
+	cliflags "github.com/storageos/go-cli/cli/flags"
+)
+
+// NewVersionedClient returns an API client for host. When tlsConfig is set
+// the client talks https to host using it, otherwise plain http.
+func NewVersionedClient(host string, version string, tlsConfig *tls.Config) (*api.Client, error) {
+	if tlsConfig == nil {
+		return api.NewVersionedClient(host, version)
+	}
+
+	// The client is created without certificates only to get an https
+	// endpoint and transport, which then get the real configuration.
+	client, err := api.NewVersionedTLSClientFromBytes(host, nil, nil, nil, version)
+	if err != nil {
+		return nil, err
+	}
+	client.TLSConfig = tlsConfig
+	if tr, ok := client.HTTPClient.Transport.(*http.Transport); ok {
+		tr.TLSClientConfig = tlsConfig
+	}
+	return client, nil
+}
+
+// newTLSConfig returns the TLS configuration set by the TLS flags, or else
+// by the context. It returns nil when TLS is not used.
+func newTLSConfig(opt *cliflags.CommonOptions, context *configfile.Context) (*tls.Config, error) {
+	switch {
+	case opt.TLSVerify && opt.TLSInsecure:
+		return nil, errors.New("Conflicting options: either specify --tlsverify or --tls-insecure, not both")
+	case opt.TLS && opt.TLSOptions != nil:
+		return tlsconfig.Client(*opt.TLSOptions)
+	case context != nil && context.TLS != nil:
+		return tlsconfig.Client(contextTLSOptions(context.TLS))
+	}
+	return nil, nil
+}
+
+// contextTLSOptions converts the TLS settings of a context. The server
+// certificate is verified, against the system CAs when no CA is given,
+// unless the context explicitly opts out.
+func contextTLSOptions(t *configfile.ContextTLS) tlsconfig.Options {
+	return tlsconfig.Options{
+		CAFile:             t.CACert,
+		CertFile:           t.Cert,
+		KeyFile:            t.Key,
+		InsecureSkipVerify: t.Insecure,
+	}
+}
@@ -0,0 +1,266 @@
+package command
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/go-connections/tlsconfig"
+	"github.com/spf13/pflag"
+
+	"github.com/storageos/go-cli/cli/config/configfile"
+	cliflags "github.com/storageos/go-cli/cli/flags"
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+// testPKI is a CA with a server certificate for 127.0.0.1 and a client
+// certificate, all written as PEM files in dir.
+type testPKI struct {
+	dir    string
+	ca     *x509.Certificate
+	caKey  *rsa.PrivateKey
+	pool   *x509.CertPool
+	server tls.Certificate
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	dir, err := ioutil.TempDir("", "tls")
+	assert.NilError(t, err)
+
+	p := &testPKI{dir: dir}
+	p.ca, p.caKey = p.issue(t, "ca", &x509.Certificate{
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+	p.pool = x509.NewCertPool()
+	p.pool.AddCert(p.ca)
+
+	server, serverKey := p.issue(t, "server", &x509.Certificate{
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	p.server = tls.Certificate{Certificate: [][]byte{server.Raw}, PrivateKey: serverKey}
+
+	p.issue(t, "client", &x509.Certificate{
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	return p
+}
+
+// issue signs tmpl with the CA, or itself when there is no CA yet, and
+// writes <name>.pem and <name>-key.pem.
+func (p *testPKI) issue(t *testing.T, name string, tmpl *x509.Certificate) (*x509.Certificate, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NilError(t, err)
+
+	tmpl.SerialNumber = big.NewInt(time.Now().UnixNano())
+	tmpl.Subject = pkix.Name{CommonName: name}
+	tmpl.NotBefore = time.Now().Add(-time.Hour)
+	tmpl.NotAfter = time.Now().Add(time.Hour)
+
+	parent, signer := tmpl, key
+	if p.ca != nil {
+		parent, signer = p.ca, p.caKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, signer)
+	assert.NilError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NilError(t, err)
+
+	p.write(t, name+".pem", &pem.Block{Type: "CERTIFICATE", Bytes: der})
+	p.write(t, name+"-key.pem", &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return cert, key
+}
+
+func (p *testPKI) write(t *testing.T, name string, block *pem.Block) {
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(p.dir, name), pem.EncodeToMemory(block), 0600))
+}
+
+func (p *testPKI) path(name string) string {
+	return filepath.Join(p.dir, name)
+}
+
+func (p *testPKI) startServer(clientAuth tls.ClientAuthType) *httptest.Server {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The client checks the API version before each request.
+		if r.URL.Path == "/version" {
+			fmt.Fprint(w, `{"apiVersion": "1"}`)
+		}
+	}))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{p.server},
+		ClientAuth:   clientAuth,
+		ClientCAs:    p.pool,
+	}
+	srv.StartTLS()
+	return srv
+}
+
+func tcpHost(srv *httptest.Server) string {
+	return "tcp://" + strings.TrimPrefix(srv.URL, "https://")
+}
+
+func TestNewAPIClientFromFlagsTLS(t *testing.T) {
+	p := newTestPKI(t)
+	defer os.RemoveAll(p.dir)
+
+	srv := p.startServer(tls.NoClientCert)
+	defer srv.Close()
+
+	mtlsSrv := p.startServer(tls.RequireAndVerifyClientCert)
+	defer mtlsSrv.Close()
+
+	other := newTestPKI(t)
+	defer os.RemoveAll(other.dir)
+
+	tests := []struct {
+		name string
+		host string
+		tls  *tlsconfig.Options
+		err  string
+	}{
+		{name: "plain http", host: tcpHost(srv), err: "HTTP request to an HTTPS server"},
+		{name: "verify", host: tcpHost(srv), tls: &tlsconfig.Options{CAFile: p.path("ca.pem")}},
+		{name: "verify with another CA", host: tcpHost(srv), tls: &tlsconfig.Options{CAFile: other.path("ca.pem")}, err: "certificate signed by unknown authority"},
+		{name: "verify with system roots", host: tcpHost(srv), tls: &tlsconfig.Options{}, err: "certificate signed by unknown authority"},
+		{name: "insecure", host: tcpHost(srv), tls: &tlsconfig.Options{InsecureSkipVerify: true}},
+		{name: "mtls without client cert", host: tcpHost(mtlsSrv), tls: &tlsconfig.Options{CAFile: p.path("ca.pem")}, err: "tls"},
+		{
+			name: "mtls",
+			host: tcpHost(mtlsSrv),
+			tls: &tlsconfig.Options{
+				CAFile:   p.path("ca.pem"),
+				CertFile: p.path("client.pem"),
+				KeyFile:  p.path("client-key.pem"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		opt := &cliflags.CommonOptions{
+			Hosts:      []string{tt.host},
+			TLS:        tt.tls != nil,
+			TLSOptions: tt.tls,
+		}
+		client, err := NewAPIClientFromFlags(opt, &configfile.ConfigFile{})
+		assert.NilError(t, err)
+
+		err = client.Ping()
+		if tt.err != "" {
+			assert.Error(t, err, tt.err)
+			continue
+		}
+		assert.NilError(t, err)
+	}
+}
+
+func TestNewAPIClientFromFlagsContextTLS(t *testing.T) {
+	p := newTestPKI(t)
+	defer os.RemoveAll(p.dir)
+
+	srv := p.startServer(tls.RequireAndVerifyClientCert)
+	defer srv.Close()
+
+	configFile := &configfile.ConfigFile{
+		Contexts: map[string]*configfile.Context{
+			"prod": {
+				Hosts: []string{tcpHost(srv)},
+				TLS: &configfile.ContextTLS{
+					CACert: p.path("ca.pem"),
+					Cert:   p.path("client.pem"),
+					Key:    p.path("client-key.pem"),
+				},
+			},
+		},
+	}
+
+	client, err := NewAPIClientFromFlags(&cliflags.CommonOptions{Context: "prod"}, configFile)
+	assert.NilError(t, err)
+	assert.NilError(t, client.Ping())
+}
+
+func TestNewAPIClientFromFlagsContextTLSVerifiesByDefault(t *testing.T) {
+	p := newTestPKI(t)
+	defer os.RemoveAll(p.dir)
+
+	srv := p.startServer(tls.RequireAndVerifyClientCert)
+	defer srv.Close()
+
+	// A client certificate without a CA still verifies the server, against
+	// the system CAs, which don't know the test CA.
+	contextTLS := &configfile.ContextTLS{
+		Cert: p.path("client.pem"),
+		Key:  p.path("client-key.pem"),
+	}
+	configFile := &configfile.ConfigFile{
+		Contexts: map[string]*configfile.Context{
+			"prod": {Hosts: []string{tcpHost(srv)}, TLS: contextTLS},
+		},
+	}
+
+	client, err := NewAPIClientFromFlags(&cliflags.CommonOptions{Context: "prod"}, configFile)
+	if err == nil {
+		err = client.Ping()
+	}
+	assert.Error(t, err, "certificate")
+
+	contextTLS.Insecure = true
+	client, err = NewAPIClientFromFlags(&cliflags.CommonOptions{Context: "prod"}, configFile)
+	assert.NilError(t, err)
+	assert.NilError(t, client.Ping())
+}
+
+func TestTLSFlagsVerifyByDefault(t *testing.T) {
+	p := newTestPKI(t)
+	defer os.RemoveAll(p.dir)
+
+	srv := p.startServer(tls.NoClientCert)
+	defer srv.Close()
+
+	other := newTestPKI(t)
+	defer os.RemoveAll(other.dir)
+
+	tests := []struct {
+		args []string
+		err  string
+	}{
+		{args: []string{"--tlscacert", p.path("ca.pem")}},
+		{args: []string{"--tlscacert", other.path("ca.pem")}, err: "certificate signed by unknown authority"},
+		{args: []string{"--tls"}, err: "certificate signed by unknown authority"},
+		{args: []string{"--tls-insecure"}},
+		{args: []string{"--tlsverify", "--tls-insecure"}, err: "Conflicting options"},
+	}
+
+	for _, tt := range tests {
+		opt := cliflags.NewCommonOptions()
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		opt.InstallFlags(flags)
+		assert.NilError(t, flags.Parse(append([]string{"-H", tcpHost(srv)}, tt.args...)))
+		opt.SetDefaultOptions(flags)
+
+		client, err := NewAPIClientFromFlags(opt, &configfile.ConfigFile{})
+		if err == nil {
+			err = client.Ping()
+		}
+		if tt.err != "" {
+			assert.Error(t, err, tt.err)
+			continue
+		}
+		assert.NilError(t, err)
+	}
+}
@@ -35,6 +35,7 @@ func filter(users []*types.User, by func(*types.User) bool) []*types.User {
 type listOptions struct {
 	quiet  bool
 	format string
+	output string
 	admins bool
 	users  bool
 }
@@ -60,6 +61,7 @@ func newListCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&opt.quiet, "quiet", "q", false, "Only display usernames")
 	flags.StringVar(&opt.format, "format", "", "Pretty-print rules using a Go template")
+	flags.StringVarP(&opt.output, "output", "o", "", formatter.OutputUsage)
 	flags.BoolVar(&opt.admins, "admin-only", false, "Only return the admin users")
 	flags.BoolVar(&opt.users, "user-only", false, "Only return the non-admin users")
 
@@ -67,6 +69,11 @@ func newListCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 }
 
 func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
+	output, err := formatter.ParseOutput(opt.output, opt.format, opt.quiet)
+	if err != nil {
+		return err
+	}
+
 	client := storageosCli.Client()
 
 	params := types.ListOptions{}
@@ -84,6 +91,9 @@ func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
 			format = formatter.TableFormatKey
 		}
 	}
+	if output.IsWide() {
+		format = formatter.WideFormatKey
+	}
 
 	sort.Sort(byUserName(users))
 	if opt.admins {
@@ -93,6 +103,10 @@ func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
 		users = filter(users, func(u *types.User) bool { return u.Role == "user" })
 	}
 
+	if output.IsStructured() {
+		return formatter.WriteOutput(storageosCli.Out(), output, formatter.UserKind, users)
+	}
+
 	userCtx := formatter.Context{
 		Output: storageosCli.Out(),
 		Format: formatter.NewUserFormat(format, opt.quiet),
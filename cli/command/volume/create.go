@@ -11,6 +11,7 @@ import (
 	"github.com/storageos/go-api/types"
 	"github.com/storageos/go-cli/cli"
 	"github.com/storageos/go-cli/cli/command"
+	cliconfig "github.com/storageos/go-cli/cli/config"
 	"github.com/storageos/go-cli/cli/opts"
 )
 
@@ -23,6 +24,8 @@ type createOptions struct {
 	namespace    string
 	nodeSelector string
 	labels       opts.ListOpts
+	mkfsOptions  []string
+	wait         waitOptions
 }
 
 func newCreateCommand(storageosCli *command.StorageOSCli) *cobra.Command {
@@ -41,7 +44,7 @@ func newCreateCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 			}
 
 			var err error
-			opt.namespace, opt.name, err = parseNamespaceVolume(opt.namespace, opt.name, posarg)
+			opt.namespace, opt.name, err = parseNamespaceVolume(opt.namespace, opt.name, posarg, storageosCli.Namespace())
 			if err != nil {
 				return err
 			}
@@ -60,6 +63,8 @@ func newCreateCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 	flags.StringVarP(&opt.namespace, "namespace", "n", "", `Volume namespace (default "default")`)
 	flags.StringVar(&opt.nodeSelector, "nodeSelector", "", "Node selector")
 	flags.Var(&opt.labels, "label", "Set metadata (key=value pairs) on the volume")
+	flags.StringArrayVar(&opt.mkfsOptions, "mkfs-opt", nil, "Option passed to mkfs when the volume is first formatted, with its value, e.g. \"-i 8192\" or \"-L my label\" (repeatable)")
+	addWaitFlags(flags, &opt.wait)
 
 	return cmd
 }
@@ -67,6 +72,19 @@ func newCreateCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 func runCreate(storageosCli *command.StorageOSCli, opt createOptions) error {
 	client := storageosCli.Client()
 
+	labels := opts.ConvertKVStringsToMap(opt.labels.GetAll())
+	if len(opt.mkfsOptions) > 0 {
+		fsType := opt.fsType
+		if fsType == "" {
+			fsType = cliconfig.DefaultFSType
+		}
+		label, err := mkfsLabel(fsType, opt.mkfsOptions)
+		if err != nil {
+			return err
+		}
+		labels[cliconfig.MkfsOptions] = label
+	}
+
 	params := types.VolumeCreateOptions{
 		Name:         opt.name,
 		Description:  opt.description,
@@ -75,7 +93,7 @@ func runCreate(storageosCli *command.StorageOSCli, opt createOptions) error {
 		FSType:       opt.fsType,
 		Namespace:    opt.namespace,
 		NodeSelector: opt.nodeSelector,
-		Labels:       opts.ConvertKVStringsToMap(opt.labels.GetAll()),
+		Labels:       labels,
 		Context:      context.Background(),
 	}
 
@@ -84,11 +102,17 @@ func runCreate(storageosCli *command.StorageOSCli, opt createOptions) error {
 		return err
 	}
 
+	if opt.wait.wait {
+		if vol, err = waitForVolume(storageosCli, opt.wait, vol.Namespace, vol.Name); err != nil {
+			return err
+		}
+	}
+
 	fmt.Fprintf(storageosCli.Out(), "%s/%s\n", vol.Namespace, vol.Name)
 	return nil
 }
 
-func parseNamespaceVolume(nsflag, vnflag, posarg string) (namespace string, volume string, err error) {
+func parseNamespaceVolume(nsflag, vnflag, posarg, defaultNamespace string) (namespace string, volume string, err error) {
 	switch {
 	case posarg != "" && vnflag != "":
 		return "", "", errors.New("Conflicting options: either specify --name or provide positional arg, not both\n")
@@ -107,14 +131,14 @@ func parseNamespaceVolume(nsflag, vnflag, posarg string) (namespace string, volu
 			return nsflag, posarg, nil
 
 		default:
-			return "default", posarg, nil
+			return defaultNamespace, posarg, nil
 		}
 
 	case vnflag != "" && nsflag != "":
 		return nsflag, vnflag, nil
 
 	case vnflag != "":
-		return "default", vnflag, nil
+		return defaultNamespace, vnflag, nil
 
 	default:
 		return "", "", errors.New("Please provide a volume name\n")
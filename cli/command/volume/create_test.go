@@ -71,7 +71,7 @@ func TestParseNamespaceVolume(t *testing.T) {
 	}
 
 	for _, fix := range fixtures {
-		n, v, err := parseNamespaceVolume(fix.namespace, fix.volumename, fix.positionalArg)
+		n, v, err := parseNamespaceVolume(fix.namespace, fix.volumename, fix.positionalArg, "default")
 
 		fails := []string{}
 
@@ -8,6 +8,7 @@ import (
 	"github.com/storageos/go-cli/cli"
 	"github.com/storageos/go-cli/cli/command"
 	"github.com/storageos/go-cli/cli/command/formatter"
+	"github.com/storageos/go-cli/pkg/selector"
 )
 
 type byVolumeName []*types.Volume
@@ -21,6 +22,7 @@ func (r byVolumeName) Less(i, j int) bool {
 type listOptions struct {
 	quiet     bool
 	format    string
+	output    string
 	selector  string
 	namespace string
 }
@@ -41,6 +43,7 @@ func newListCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&opt.quiet, "quiet", "q", false, "Only display volume names")
 	flags.StringVar(&opt.format, "format", "", "Pretty-print volumes using a Go template")
+	flags.StringVarP(&opt.output, "output", "o", "", formatter.OutputUsage)
 	flags.StringVarP(&opt.selector, "selector", "s", "", "Provide selector (e.g. to list all volumes with label app=cassandra ' --selector=app=cassandra')")
 	flags.StringVarP(&opt.namespace, "namespace", "n", "", "Namespace scope")
 
@@ -48,6 +51,16 @@ func newListCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 }
 
 func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
+	output, err := formatter.ParseOutput(opt.output, opt.format, opt.quiet)
+	if err != nil {
+		return err
+	}
+
+	sel, err := selector.Parse(opt.selector)
+	if err != nil {
+		return err
+	}
+
 	client := storageosCli.Client()
 
 	params := types.ListOptions{
@@ -60,8 +73,17 @@ func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
 	if err != nil {
 		return err
 	}
+	if !sel.Empty() {
+		matched := volumes[:0]
+		for _, vol := range volumes {
+			if sel.Matches(vol.Labels) {
+				matched = append(matched, vol)
+			}
+		}
+		volumes = matched
+	}
 
-	nodes, err := client.ControllerList(params)
+	nodes, err := client.ControllerList(types.ListOptions{})
 	if err != nil {
 		return err
 	}
@@ -74,9 +96,16 @@ func runList(storageosCli *command.StorageOSCli, opt listOptions) error {
 			format = formatter.TableFormatKey
 		}
 	}
+	if output.IsWide() {
+		format = formatter.WideFormatKey
+	}
 
 	sort.Sort(byVolumeName(volumes))
 
+	if output.IsStructured() {
+		return formatter.WriteOutput(storageosCli.Out(), output, formatter.VolumeKind, volumes)
+	}
+
 	volumeCtx := formatter.Context{
 		Output: storageosCli.Out(),
 		Format: formatter.NewVolumeFormat(format, opt.quiet),
@@ -0,0 +1,42 @@
+package volume
+
+import (
+	"encoding/json"
+	"fmt"
+
+	cliconfig "github.com/storageos/go-cli/cli/config"
+	"github.com/storageos/go-cli/pkg/validation"
+)
+
+// mkfsLabel returns the value of the mkfs options label for options given
+// with --mkfs-opt: the mkfs arguments, checked for the filesystem type, as a
+// JSON array.
+func mkfsLabel(fsType string, options []string) (string, error) {
+	args, err := validation.ParseMkfsOptions(fsType, options)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// mkfsArgs returns the mkfs arguments saved in the labels of a volume. As
+// anyone who can label the volume can change them, they are checked for the
+// filesystem type.
+func mkfsArgs(fsType string, labels map[string]string) ([]string, error) {
+	value, ok := labels[cliconfig.MkfsOptions]
+	if !ok || value == "" {
+		return nil, nil
+	}
+	var args []string
+	if err := json.Unmarshal([]byte(value), &args); err != nil {
+		return nil, fmt.Errorf("label %s must be a JSON array of mkfs arguments: %v", cliconfig.MkfsOptions, err)
+	}
+	if err := validation.IsValidMkfsArgs(fsType, args); err != nil {
+		return nil, fmt.Errorf("label %s: %v", cliconfig.MkfsOptions, err)
+	}
+	return args, nil
+}
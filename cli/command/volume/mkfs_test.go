@@ -0,0 +1,34 @@
+package volume
+
+import (
+	"testing"
+
+	cliconfig "github.com/storageos/go-cli/cli/config"
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+func TestMkfsLabel(t *testing.T) {
+	label, err := mkfsLabel("ext4", []string{"-i 8192", "-L my label"})
+	assert.NilError(t, err)
+	assert.Equal(t, label, `["-i","8192","-L","my label"]`)
+
+	args, err := mkfsArgs("ext4", map[string]string{cliconfig.MkfsOptions: label})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, args, []string{"-i", "8192", "-L", "my label"})
+
+	_, err = mkfsLabel("ext4", []string{"-d /etc"})
+	assert.Error(t, err, `mkfs option "-d" not supported by ext4`)
+}
+
+func TestMkfsArgsFromEditedLabel(t *testing.T) {
+	args, err := mkfsArgs("ext4", map[string]string{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(args), 0)
+
+	// Labels can be set by anyone who can update the volume, or by rules.
+	_, err = mkfsArgs("ext4", map[string]string{cliconfig.MkfsOptions: "-i 8192"})
+	assert.Error(t, err, "must be a JSON array")
+
+	_, err = mkfsArgs("ext4", map[string]string{cliconfig.MkfsOptions: `["-U","0000"]`})
+	assert.Error(t, err, `mkfs option "-U" not supported by ext4`)
+}
@@ -18,15 +18,20 @@ import (
 	"github.com/storageos/go-cli/pkg/system"
 	"github.com/storageos/go-cli/pkg/validation"
 
+	api "github.com/storageos/go-api"
 	"github.com/storageos/go-api/types"
 
 	log "github.com/sirupsen/logrus"
 )
 
 type mountOptions struct {
-	ref        string
-	mountpoint string // mountpoint
-	fsType     string
+	ref          string
+	mountpoint   string // mountpoint
+	fsType       string
+	readOnly     bool
+	mountOptions []string
+	mkfsOptions  []string
+	wait         waitOptions
 }
 
 func newMountCommand(storageosCli *command.StorageOSCli) *cobra.Command {
@@ -45,6 +50,10 @@ func newMountCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.StringVarP(&opt.fsType, "fsType", "m", cliconfig.DefaultFSType, `Volume fs type`)
+	flags.BoolVar(&opt.readOnly, "read-only", false, "Mount the volume read-only")
+	flags.StringSliceVarP(&opt.mountOptions, "option", "o", nil, "Mount option supported by the fs type, e.g. noatime or discard (repeatable)")
+	flags.StringArrayVar(&opt.mkfsOptions, "mkfs-opt", nil, "Option passed to mkfs if this mount formats the volume, saved for other nodes (repeatable)")
+	addWaitFlags(flags, &opt.wait)
 
 	return cmd
 }
@@ -67,9 +76,14 @@ func runMount(storageosCli *command.StorageOSCli, opt mountOptions) error {
 	if err != nil {
 		return err
 	}
+	for _, o := range opt.mountOptions {
+		if err := validation.IsValidMountOption(opt.fsType, o); err != nil {
+			return err
+		}
+	}
 
 	client := storageosCli.Client()
-	namespace, name, err := validation.ParseRefWithDefault(opt.ref)
+	namespace, name, err := validation.ParseRefInNamespace(opt.ref, storageosCli.Namespace())
 	if err != nil {
 		return err
 	}
@@ -79,11 +93,40 @@ func runMount(storageosCli *command.StorageOSCli, opt mountOptions) error {
 		return err
 	}
 
+	if opt.wait.wait {
+		if vol, err = waitForVolume(storageosCli, opt.wait, namespace, name); err != nil {
+			return err
+		}
+	}
+
 	// checking readiness
 	if err := isVolumeReady(vol, name); err != nil {
 		return fmt.Errorf("cannot mount volume: %v", err)
 	}
 
+	mkfs := vol.MkfsDoneAt.IsZero() && !vol.MkfsDone
+	if len(opt.mkfsOptions) > 0 {
+		if !mkfs {
+			return fmt.Errorf("volume %s is already formatted, --mkfs-opt only applies to the first mount", vol.Name)
+		}
+		// Persist the options so the volume is formatted the same way if
+		// this mount fails and another node formats it.
+		label, err := mkfsLabel(opt.fsType, opt.mkfsOptions)
+		if err != nil {
+			return err
+		}
+		if err := setVolumeLabel(client, vol, cliconfig.MkfsOptions, label); err != nil {
+			return fmt.Errorf("failed to save mkfs options: %v", err)
+		}
+	}
+
+	var mkfsOptions []string
+	if mkfs {
+		if mkfsOptions, err = mkfsArgs(opt.fsType, vol.Labels); err != nil {
+			return fmt.Errorf("cannot format volume %s: %v", vol.Name, err)
+		}
+	}
+
 	var hostname string
 
 	// getting current hostname
@@ -102,7 +145,12 @@ func runMount(storageosCli *command.StorageOSCli, opt mountOptions) error {
 		return err
 	}
 
-	err = retryableMount(vol, opt.mountpoint, opt.fsType)
+	mountOpts := mount.MountOptions{
+		ReadOnly:    opt.readOnly,
+		Options:     opt.mountOptions,
+		MkfsOptions: mkfsOptions,
+	}
+	err = retryableMount(vol, opt.mountpoint, opt.fsType, mountOpts, mkfs)
 	if err != nil {
 		log.WithFields(log.Fields{
 			"namespace":  namespace,
@@ -121,12 +169,20 @@ func runMount(storageosCli *command.StorageOSCli, opt mountOptions) error {
 		return fmt.Errorf("Failed to mount: %v", err)
 	}
 
+	readOnly := ""
+	if opt.readOnly {
+		readOnly = "true"
+	}
+	if err := setVolumeLabel(client, vol, cliconfig.MountReadOnly, readOnly); err != nil {
+		fmt.Fprintf(storageosCli.Err(), "WARNING: failed to record read-only mount of volume %s: %v\n", vol.Name, err)
+	}
+
 	fmt.Printf("volume %s mounted: %s\n", vol.Name, opt.mountpoint)
 
 	return nil
 }
 
-func retryableMount(volume *types.Volume, mountpoint, fsType string) error {
+func retryableMount(volume *types.Volume, mountpoint, fsType string, opts mount.MountOptions, mkfs bool) error {
 
 	driver := mount.New(cliconfig.DeviceRootPath)
 
@@ -138,7 +194,7 @@ RETRY:
 	// Perform the mount
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
-	err := driver.MountVolume(ctx, volume.ID, mountpoint, fsType, volume.MkfsDoneAt.IsZero() && !volume.MkfsDone)
+	err := driver.MountVolume(ctx, volume.ID, mountpoint, fsType, opts, mkfs)
 	if err != nil {
 		log.WithFields(log.Fields{
 			"volume_id":  volume.ID,
@@ -171,3 +227,37 @@ func isVolumeReady(vol *types.Volume, ref string) error {
 
 	return nil
 }
+
+// setVolumeLabel sets a label managed by the CLI on the volume, or removes
+// it if value is empty, so that other clients can see it.
+func setVolumeLabel(client *api.Client, vol *types.Volume, key, value string) error {
+	if current, ok := vol.Labels[key]; current == value && ok == (value != "") {
+		return nil
+	}
+
+	labels := make(map[string]string, len(vol.Labels)+1)
+	for k, v := range vol.Labels {
+		labels[k] = v
+	}
+	if value != "" {
+		labels[key] = value
+	} else {
+		delete(labels, key)
+	}
+
+	_, err := client.VolumeUpdate(types.VolumeUpdateOptions{
+		ID:           vol.ID,
+		Name:         vol.Name,
+		Namespace:    vol.Namespace,
+		Description:  vol.Description,
+		Size:         vol.Size,
+		NodeSelector: vol.NodeSelector,
+		Labels:       labels,
+		Context:      context.Background(),
+	})
+	if err != nil {
+		return err
+	}
+	vol.Labels = labels
+	return nil
+}
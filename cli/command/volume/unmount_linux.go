@@ -62,7 +62,7 @@ func runUnmount(storageosCli *command.StorageOSCli, opt unmountOptions, mountDri
 	}
 
 	client := storageosCli.Client()
-	namespace, name, err := validation.ParseRefWithDefault(opt.ref)
+	namespace, name, err := validation.ParseRefInNamespace(opt.ref, storageosCli.Namespace())
 	if err != nil {
 		return err
 	}
@@ -104,6 +104,10 @@ func runUnmount(storageosCli *command.StorageOSCli, opt unmountOptions, mountDri
 		return fmt.Errorf("unable to unmount volume, error: %s", err)
 	}
 
+	if err := setVolumeLabel(client, vol, cliconfig.MountReadOnly, ""); err != nil {
+		fmt.Fprintf(storageosCli.Err(), "WARNING: failed to clear read-only mount label of volume %s: %v\n", vol.Name, err)
+	}
+
 	fmt.Printf("volume %s unmounted: %s\n", vol.Name, vol.Mountpoint)
 	return nil
 }
@@ -27,6 +27,7 @@ type updateOptions struct {
 	description string
 	size        int
 	labels      opts.ListOpts
+	wait        waitOptions
 }
 
 var (
@@ -43,24 +44,41 @@ func newUpdateCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 		Short: "Update a volume",
 		Args:  cli.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runUpdate(storageosCli, cmd.Flags(), args[0])
+			return runUpdate(storageosCli, opt, cmd.Flags(), args[0])
 		},
 	}
 
 	flags := cmd.Flags()
 	flags.StringVarP(&opt.description, flagDescription, "d", "", `Volume description`)
-	flags.IntVarP(&opt.size, flagSize, "s", 5, "Volume size in GB")
+	flags.IntVarP(&opt.size, flagSize, "s", 5, "Volume size in GB, volumes can only grow")
 	flags.Var(&opt.labels, flagLabelAdd, "Add or update a volume label (key=value)")
 	labelKeys := opts.NewListOpts(nil)
 	flags.Var(&labelKeys, flagLabelRemove, "Remove a volume label if exists")
+	addWaitFlags(flags, &opt.wait)
 	return cmd
 }
 
-func runUpdate(storageosCli *command.StorageOSCli, flags *pflag.FlagSet, ref string) error {
+func runUpdate(storageosCli *command.StorageOSCli, opt updateOptions, flags *pflag.FlagSet, ref string) error {
 	success := func(_ string) {
 		fmt.Fprintln(storageosCli.Out(), ref)
 	}
-	return updateVolumes(storageosCli, []string{ref}, mergeVolumeUpdate(flags), success)
+	if !opt.wait.wait {
+		return updateVolumes(storageosCli, []string{ref}, mergeVolumeUpdate(flags), success)
+	}
+
+	// Only report the update once the volume has settled.
+	if err := updateVolumes(storageosCli, []string{ref}, mergeVolumeUpdate(flags), func(string) {}); err != nil {
+		return err
+	}
+	namespace, name, err := validation.ParseRefInNamespace(ref, storageosCli.Namespace())
+	if err != nil {
+		return err
+	}
+	if _, err := waitForVolume(storageosCli, opt.wait, namespace, name); err != nil {
+		return err
+	}
+	success(name)
+	return nil
 }
 
 func updateVolumes(storageosCli *command.StorageOSCli, refs []string, mergeVolume func(volume *types.Volume) error, success func(name string)) error {
@@ -69,7 +87,7 @@ func updateVolumes(storageosCli *command.StorageOSCli, refs []string, mergeVolum
 
 	for _, ref := range refs {
 
-		namespace, name, err := validation.ParseRefWithDefault(ref)
+		namespace, name, err := validation.ParseRefInNamespace(ref, storageosCli.Namespace())
 		if err != nil {
 			return err
 		}
@@ -79,6 +97,7 @@ func updateVolumes(storageosCli *command.StorageOSCli, refs []string, mergeVolum
 			return err
 		}
 
+		size := volume.Size
 		err = mergeVolume(volume)
 		if err != nil {
 			return err
@@ -95,6 +114,11 @@ func updateVolumes(storageosCli *command.StorageOSCli, refs []string, mergeVolum
 		if err != nil {
 			return err
 		}
+		if volume.Size > size {
+			if err := resizeFilesystem(storageosCli, volume); err != nil {
+				return err
+			}
+		}
 		success(name)
 	}
 	return nil
@@ -114,6 +138,9 @@ func mergeVolumeUpdate(flags *pflag.FlagSet) func(*types.Volume) error {
 			if err != nil {
 				return err
 			}
+			if gb < volume.Size {
+				return fmt.Errorf("cannot shrink volume %s from %dGB to %dGB, volumes can only grow", volume.Name, volume.Size, gb)
+			}
 			volume.Size = gb
 		}
 		if volume.Labels == nil {
@@ -0,0 +1,47 @@
+// +build linux
+
+package volume
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+	"time"
+
+	units "github.com/docker/go-units"
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/cli/command"
+	cliconfig "github.com/storageos/go-cli/cli/config"
+	"github.com/storageos/go-cli/pkg/host"
+	"github.com/storageos/go-cli/pkg/mount"
+)
+
+// resizeTimeout is how long to wait for the device to grow and the
+// filesystem to be resized.
+const resizeTimeout = 60 * time.Second
+
+// resizeFilesystem grows the filesystem of a volume mounted on this host to
+// its new size. Volumes mounted elsewhere are left to the node mounting them.
+func resizeFilesystem(storageosCli *command.StorageOSCli, vol *types.Volume) error {
+	hostname, err := host.Get()
+	if err != nil || vol.MountedBy == "" || vol.MountedBy != hostname {
+		return nil
+	}
+
+	// must be root
+	if euid := syscall.Geteuid(); euid != 0 {
+		return fmt.Errorf("volume %s resized, growing its filesystem requires root permission - try prefixing command with `sudo`", vol.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), resizeTimeout)
+	defer cancel()
+
+	driver := mount.New(cliconfig.DeviceRootPath)
+	usable, err := driver.ResizeVolume(ctx, vol.ID, vol.Mountpoint, uint64(vol.Size)*units.GiB)
+	if err != nil {
+		return fmt.Errorf("volume %s resized, but failed to grow its filesystem: %v", vol.Name, err)
+	}
+
+	fmt.Fprintf(storageosCli.Out(), "volume %s filesystem resized: %s usable\n", vol.Name, units.BytesSize(float64(usable)))
+	return nil
+}
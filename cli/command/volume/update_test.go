@@ -0,0 +1,35 @@
+package volume
+
+import (
+	"testing"
+
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+func TestMergeVolumeUpdateSize(t *testing.T) {
+	tests := []struct {
+		size     string
+		expected int
+		err      string
+	}{
+		{size: "10", expected: 10},
+		{size: "20", expected: 20},
+		{size: "5", err: "cannot shrink volume db01 from 10GB to 5GB"},
+	}
+
+	for _, tt := range tests {
+		cmd := newUpdateCommand(nil)
+		flags := cmd.Flags()
+		assert.NilError(t, flags.Parse([]string{"--size", tt.size}))
+
+		volume := &types.Volume{Name: "db01", Size: 10}
+		err := mergeVolumeUpdate(flags)(volume)
+		if tt.err != "" {
+			assert.Error(t, err, tt.err)
+			continue
+		}
+		assert.NilError(t, err)
+		assert.Equal(t, volume.Size, tt.expected)
+	}
+}
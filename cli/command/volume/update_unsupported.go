@@ -0,0 +1,13 @@
+// +build !linux
+
+package volume
+
+import (
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/cli/command"
+)
+
+// Filesystems are only resized on linux, where volumes can be mounted.
+func resizeFilesystem(storageosCli *command.StorageOSCli, vol *types.Volume) error {
+	return nil
+}
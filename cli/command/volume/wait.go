@@ -0,0 +1,102 @@
+package volume
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/cli/command"
+	cliconfig "github.com/storageos/go-cli/cli/config"
+)
+
+const (
+	volumeStatusActive = "active"
+	volumeStatusFailed = "failed"
+	volumeHealthy      = "healthy"
+
+	waitPollInterval = time.Second
+	defaultWait      = 5 * time.Minute
+)
+
+// waitOptions are the flags shared by commands that can block until the
+// volume is ready.
+type waitOptions struct {
+	wait    bool
+	timeout time.Duration
+}
+
+func addWaitFlags(flags *pflag.FlagSet, opt *waitOptions) {
+	flags.BoolVar(&opt.wait, "wait", false, "Wait for the volume to be active, healthy and fully replicated")
+	flags.DurationVar(&opt.timeout, "timeout", defaultWait, "Time to wait for the volume with --wait")
+}
+
+// waitForVolume polls the volume until it is ready, printing progress to
+// stderr whenever it changes so that stdout stays usable by scripts.
+func waitForVolume(storageosCli *command.StorageOSCli, opt waitOptions, namespace, name string) (*types.Volume, error) {
+	client := storageosCli.Client()
+
+	ctx, cancel := context.WithTimeout(context.Background(), opt.timeout)
+	defer cancel()
+
+	var vol *types.Volume
+	var last string
+	err := command.Poll(ctx, waitPollInterval, func() (bool, error) {
+		v, err := client.Volume(namespace, name)
+		if err != nil {
+			return false, err
+		}
+		vol = v
+
+		ready, progress, err := volumeReady(v)
+		if progress != last {
+			fmt.Fprintf(storageosCli.Err(), "%s/%s: %s\n", namespace, name, progress)
+			last = progress
+		}
+		return ready, err
+	})
+	if err == context.DeadlineExceeded {
+		return nil, fmt.Errorf("timed out after %v waiting for volume %s/%s to be ready (%s)", opt.timeout, namespace, name, last)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return vol, nil
+}
+
+// volumeReady returns true if the volume is active, healthy and has as many
+// healthy replicas as requested by its replicas label, along with a summary
+// of its state. Volumes that failed to provision return an error.
+func volumeReady(vol *types.Volume) (bool, string, error) {
+	desired, _ := strconv.Atoi(vol.Labels[cliconfig.FeatureReplicas])
+	replicas := 0
+	for _, r := range vol.Replicas {
+		if r != nil && isHealthy(r.Health) {
+			replicas++
+		}
+	}
+
+	health := vol.Health
+	if health == "" {
+		health = "-"
+	}
+	progress := fmt.Sprintf("status %s, health %s, replicas %d/%d", vol.Status, health, replicas, desired)
+
+	if vol.Status == volumeStatusFailed {
+		if vol.StatusMessage != "" {
+			return false, progress, fmt.Errorf("volume %s/%s failed: %s", vol.Namespace, vol.Name, vol.StatusMessage)
+		}
+		return false, progress, fmt.Errorf("volume %s/%s failed", vol.Namespace, vol.Name)
+	}
+
+	ready := vol.Status == volumeStatusActive && isHealthy(vol.Health) && replicas >= desired
+	return ready, progress, nil
+}
+
+// isHealthy treats an unreported health as healthy, as not all versions of
+// the API report it.
+func isHealthy(health string) bool {
+	return health == "" || health == volumeHealthy
+}
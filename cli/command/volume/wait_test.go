@@ -0,0 +1,79 @@
+package volume
+
+import (
+	"testing"
+
+	"github.com/storageos/go-api/types"
+	cliconfig "github.com/storageos/go-cli/cli/config"
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+func TestVolumeReady(t *testing.T) {
+	replicas := func(health ...string) []*types.Deployment {
+		var d []*types.Deployment
+		for _, h := range health {
+			d = append(d, &types.Deployment{Health: h})
+		}
+		return d
+	}
+	withReplicas := map[string]string{cliconfig.FeatureReplicas: "2"}
+
+	tests := []struct {
+		name     string
+		vol      *types.Volume
+		ready    bool
+		progress string
+		err      string
+	}{
+		{
+			name:     "pending",
+			vol:      &types.Volume{Status: "pending"},
+			progress: "status pending, health -, replicas 0/0",
+		},
+		{
+			name:     "active",
+			vol:      &types.Volume{Status: "active", Health: "healthy"},
+			ready:    true,
+			progress: "status active, health healthy, replicas 0/0",
+		},
+		{
+			name:     "active without health",
+			vol:      &types.Volume{Status: "active"},
+			ready:    true,
+			progress: "status active, health -, replicas 0/0",
+		},
+		{
+			name:     "degraded",
+			vol:      &types.Volume{Status: "active", Health: "degraded"},
+			progress: "status active, health degraded, replicas 0/0",
+		},
+		{
+			name:     "replicas syncing",
+			vol:      &types.Volume{Status: "active", Health: "healthy", Labels: withReplicas, Replicas: replicas("healthy", "syncing")},
+			progress: "status active, health healthy, replicas 1/2",
+		},
+		{
+			name:     "replicas ready",
+			vol:      &types.Volume{Status: "active", Health: "healthy", Labels: withReplicas, Replicas: replicas("healthy", "healthy")},
+			ready:    true,
+			progress: "status active, health healthy, replicas 2/2",
+		},
+		{
+			name:     "failed",
+			vol:      &types.Volume{Namespace: "default", Name: "db01", Status: "failed", StatusMessage: "no capacity"},
+			progress: "status failed, health -, replicas 0/0",
+			err:      "volume default/db01 failed: no capacity",
+		},
+	}
+
+	for _, tt := range tests {
+		ready, progress, err := volumeReady(tt.vol)
+		if tt.err != "" {
+			assert.Error(t, err, tt.err)
+		} else {
+			assert.NilError(t, err)
+		}
+		assert.Equal(t, ready, tt.ready, tt.name)
+		assert.Equal(t, progress, tt.progress, tt.name)
+	}
+}
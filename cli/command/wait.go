@@ -0,0 +1,29 @@
+package command
+
+import (
+	"context"
+	"time"
+)
+
+// Poll calls condition every interval until it reports true or returns an
+// error. It gives up with ctx.Err() once ctx is done.
+func Poll(ctx context.Context, interval time.Duration, condition func() (bool, error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		done, err := condition()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
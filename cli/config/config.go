@@ -22,6 +22,9 @@ const (
 	EnvStorageosUsername   = "STORAGEOS_USERNAME"
 	EnvStorageosPassword   = "STORAGEOS_PASSWORD"
 	EnvStorageosAPIVersion = "STORAGEOS_API_VERSION"
+	EnvStorageOSContext    = "STORAGEOS_CONTEXT"
+	EnvStorageOSCertPath   = "STORAGEOS_CERT_PATH"
+	EnvStorageOSTLSVerify  = "STORAGEOS_TLS_VERIFY"
 )
 
 var (
@@ -33,6 +36,16 @@ const (
 	FeatureReplicas = "storageos.feature.replicas"
 )
 
+// volume labels managed by the CLI
+const (
+	// MountReadOnly is set while a volume is mounted read-only.
+	MountReadOnly = "storageos.mount.readonly"
+
+	// MkfsOptions holds the arguments passed to mkfs when the first mount
+	// formats the volume, as a JSON array.
+	MkfsOptions = "storageos.mkfs.options"
+)
+
 // DeviceRootPath defines the directory in which the raw StorageOS volumes are
 // created.
 const DeviceRootPath = "/var/lib/storageos/volumes"
@@ -0,0 +1,98 @@
+package configfile
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Context is a named cluster, holding everything needed to talk to it.
+type Context struct {
+	Hosts     []string    `json:"hosts"`
+	Username  string      `json:"username,omitempty"`
+	Namespace string      `json:"namespace,omitempty"`
+	TLS       *ContextTLS `json:"tls,omitempty"`
+	Formats   *Formats    `json:"formats,omitempty"`
+}
+
+// ContextTLS holds the paths of the TLS files used to reach a context's
+// cluster. TLS is enabled as soon as it is set, and the server is verified,
+// against CACert or else the system CAs, unless Insecure is set.
+type ContextTLS struct {
+	CACert string `json:"cacert,omitempty"`
+	Cert   string `json:"cert,omitempty"`
+	Key    string `json:"key,omitempty"`
+	// Verify is kept for configs written when verification was optional.
+	Verify   bool `json:"verify,omitempty"`
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// GetContext returns the named context.
+func (configFile *ConfigFile) GetContext(name string) (*Context, error) {
+	ctx, ok := configFile.Contexts[name]
+	if !ok {
+		return nil, fmt.Errorf("context %q not found, see 'storageos context ls'", name)
+	}
+	return ctx, nil
+}
+
+// ContextNames returns the names of all contexts, sorted.
+func (configFile *ConfigFile) ContextNames() []string {
+	names := make([]string, 0, len(configFile.Contexts))
+	for name := range configFile.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyFormats overrides the output formats with the ones set in f, for the
+// lifetime of the ConfigFile only. Saving writes the original formats back.
+func (configFile *ConfigFile) ApplyFormats(f *Formats) {
+	if f == nil {
+		return
+	}
+	if configFile.globalFormats == nil {
+		global := configFile.Formats
+		configFile.globalFormats = &global
+	}
+	configFile.Formats.merge(f)
+}
+
+// Set sets the format of kind, which is the name of a list command's
+// objects such as "volumes" or "nodeHealth".
+func (f *Formats) Set(kind, format string) error {
+	field, ok := f.fields()[kind]
+	if !ok {
+		return fmt.Errorf("unknown format %q, expected one of: %s", kind, formatKinds)
+	}
+	*field = format
+	return nil
+}
+
+// merge sets the non-empty formats of o on f.
+func (f *Formats) merge(o *Formats) {
+	fields := f.fields()
+	for kind, v := range o.fields() {
+		if *v != "" {
+			*fields[kind] = *v
+		}
+	}
+}
+
+const formatKinds = "volumes, pools, namespaces, rules, users, policies, templates, events, contexts, clusterHealth, nodeHealth"
+
+func (f *Formats) fields() map[string]*string {
+	return map[string]*string{
+		"volumes":       &f.VolumesFormat,
+		"pools":         &f.PoolsFormat,
+		"namespaces":    &f.NamespacesFormat,
+		"rules":         &f.RulesFormat,
+		"users":         &f.UsersFormat,
+		"policies":      &f.PoliciesFormat,
+		"templates":     &f.TemplatesFormat,
+		"events":        &f.EventsFormat,
+		"contexts":      &f.ContextsFormat,
+		"clusterHealth": &f.ClusterHealthFormat,
+		"nodeHealth":    &f.NodeHealthFormat,
+	}
+}
@@ -0,0 +1,61 @@
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestApplyFormatsIsNotSaved(t *testing.T) {
+	configFile := &ConfigFile{
+		Formats: Formats{VolumesFormat: "global", PoolsFormat: "pools"},
+		Contexts: map[string]*Context{
+			"dev": {Hosts: []string{"10.0.0.1"}, Formats: &Formats{VolumesFormat: "dev"}},
+		},
+	}
+
+	ctx, err := configFile.GetContext("dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	configFile.ApplyFormats(ctx.Formats)
+
+	if configFile.VolumesFormat != "dev" {
+		t.Errorf("expected context volumes format to apply, got %q", configFile.VolumesFormat)
+	}
+	if configFile.PoolsFormat != "pools" {
+		t.Errorf("expected global pools format to be kept, got %q", configFile.PoolsFormat)
+	}
+
+	var buf bytes.Buffer
+	if err := configFile.SaveToWriter(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var saved map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved["volumesFormat"] != "global" {
+		t.Errorf("expected global volumes format to be saved, got %v", saved["volumesFormat"])
+	}
+}
+
+func TestGetContextMissing(t *testing.T) {
+	configFile := &ConfigFile{}
+	if _, err := configFile.GetContext("prod"); err == nil {
+		t.Error("expected an error for a missing context")
+	}
+}
+
+func TestFormatsSet(t *testing.T) {
+	var f Formats
+	if err := f.Set("nodeHealth", "table {{.Name}}"); err != nil {
+		t.Fatal(err)
+	}
+	if f.NodeHealthFormat != "table {{.Name}}" {
+		t.Errorf("unexpected node health format %q", f.NodeHealthFormat)
+	}
+	if err := f.Set("bogus", "x"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
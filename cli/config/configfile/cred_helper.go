@@ -0,0 +1,127 @@
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const (
+	// credentialHelperPrefix is the prefix of the docker-style credential
+	// helper programs, e.g. docker-credential-pass.
+	credentialHelperPrefix = "docker-credential-"
+
+	// credentialHelperNotFound is printed by helpers that hold no
+	// credentials for a server.
+	credentialHelperNotFound = "credentials not found in native keychain"
+
+	// credentialServerPrefix keeps StorageOS hosts apart from the registries
+	// stored by docker in the same helper.
+	credentialServerPrefix = "storageos://"
+)
+
+// PasswordStore keeps passwords out of the config file.
+type PasswordStore interface {
+	// Get returns the password of host, or ErrNotFound.
+	Get(host string) (string, error)
+	Store(host, username, password string) error
+	Erase(host string) error
+}
+
+// NewPasswordStore returns the store named name, which is either "keychain"
+// for the macOS keychain or the name of a docker-credential-* helper.
+func NewPasswordStore(name string) PasswordStore {
+	if name == keychainStoreName {
+		return keychainStore{}
+	}
+	return &helperStore{program: credentialHelperPrefix + name}
+}
+
+// DetectDefaultStore sets the credentials store to a helper found in the
+// PATH, for config files not holding any credentials yet.
+func DetectDefaultStore(configFile *ConfigFile) {
+	if configFile.CredsStore != "" || len(configFile.CredentialsStore) > 0 {
+		return
+	}
+	if runtime.GOOS != "linux" {
+		return
+	}
+	if _, err := exec.LookPath(credentialHelperPrefix + "secretservice"); err == nil {
+		configFile.CredsStore = "secretservice"
+	}
+}
+
+// helperCredentials is the payload exchanged with credential helpers.
+type helperCredentials struct {
+	ServerURL string
+	Username  string
+	Secret    string
+}
+
+// helperStore talks to a docker-credential-* helper over stdin and stdout.
+type helperStore struct {
+	program string
+}
+
+func (h *helperStore) Get(host string) (string, error) {
+	out, err := h.run("get", strings.NewReader(credentialServerPrefix+host))
+	if err != nil {
+		if strings.TrimSpace(string(out)) == credentialHelperNotFound {
+			return "", ErrNotFound
+		}
+		return "", h.error(out, err)
+	}
+
+	var creds helperCredentials
+	if err := json.Unmarshal(out, &creds); err != nil {
+		return "", fmt.Errorf("%s: invalid response: %v", h.program, err)
+	}
+	return creds.Secret, nil
+}
+
+func (h *helperStore) Store(host, username, password string) error {
+	payload, err := json.Marshal(helperCredentials{
+		ServerURL: credentialServerPrefix + host,
+		Username:  username,
+		Secret:    password,
+	})
+	if err != nil {
+		return err
+	}
+
+	if out, err := h.run("store", bytes.NewReader(payload)); err != nil {
+		return h.error(out, err)
+	}
+	return nil
+}
+
+func (h *helperStore) Erase(host string) error {
+	out, err := h.run("erase", strings.NewReader(credentialServerPrefix+host))
+	if err != nil && strings.TrimSpace(string(out)) != credentialHelperNotFound {
+		return h.error(out, err)
+	}
+	return nil
+}
+
+func (h *helperStore) run(action string, stdin io.Reader) ([]byte, error) {
+	cmd := exec.Command(h.program, action)
+	cmd.Stdin = stdin
+	return cmd.Output()
+}
+
+// error builds an error from the helper's output, as helpers print their
+// errors on stdout.
+func (h *helperStore) error(out []byte, err error) error {
+	if _, ok := err.(*exec.Error); ok {
+		return fmt.Errorf("credentials store %s not found in PATH", h.program)
+	}
+	if msg := strings.TrimSpace(string(out)); msg != "" {
+		return errors.New(h.program + ": " + msg)
+	}
+	return fmt.Errorf("%s: %v", h.program, err)
+}
@@ -0,0 +1,149 @@
+package configfile
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	fakeHelperName = "fake"
+	fakeHelperDir  = "FAKE_CREDENTIAL_HELPER_DIR"
+)
+
+// TestMain makes the test binary act as docker-credential-fake when invoked
+// under that name, keeping credentials as files in $FAKE_CREDENTIAL_HELPER_DIR.
+func TestMain(m *testing.M) {
+	if filepath.Base(os.Args[0]) == credentialHelperPrefix+fakeHelperName {
+		os.Exit(fakeHelper(os.Args[1]))
+	}
+	os.Exit(m.Run())
+}
+
+func fakeHelper(action string) int {
+	in, _ := ioutil.ReadAll(os.Stdin)
+	dir := os.Getenv(fakeHelperDir)
+	path := func(server string) string {
+		return filepath.Join(dir, base64.URLEncoding.EncodeToString([]byte(server)))
+	}
+
+	switch action {
+	case "store":
+		var creds helperCredentials
+		if err := json.Unmarshal(in, &creds); err != nil {
+			fmt.Print(err)
+			return 1
+		}
+		if creds.Secret == "fail" {
+			fmt.Print("store refused")
+			return 1
+		}
+		ioutil.WriteFile(path(creds.ServerURL), in, 0600)
+	case "get":
+		data, err := ioutil.ReadFile(path(string(in)))
+		if err != nil {
+			fmt.Print(credentialHelperNotFound)
+			return 1
+		}
+		os.Stdout.Write(data)
+	case "erase":
+		if err := os.Remove(path(string(in))); err != nil {
+			fmt.Print(credentialHelperNotFound)
+			return 1
+		}
+	default:
+		fmt.Printf("unknown action %q", action)
+		return 1
+	}
+	return 0
+}
+
+// installFakeHelper puts docker-credential-fake first in the PATH.
+func installFakeHelper(t *testing.T) (cleanup func()) {
+	dir, err := ioutil.TempDir("", "credhelper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := filepath.Join(dir, "store")
+	if err := os.Mkdir(store, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(self, filepath.Join(dir, credentialHelperPrefix+fakeHelperName)); err != nil {
+		t.Fatal(err)
+	}
+
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+path)
+	os.Setenv(fakeHelperDir, store)
+	return func() {
+		os.Setenv("PATH", path)
+		os.Unsetenv(fakeHelperDir)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestHelperStore(t *testing.T) {
+	defer installFakeHelper(t)()
+
+	configFile := &ConfigFile{CredentialsStore: make(CredStore), CredsStore: fakeHelperName}
+
+	if err := configFile.SetCredentials("10.1.5.249:5705", "admin", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the password must not end up in the config file
+	data, err := json.Marshal(configFile.CredentialsStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "password") {
+		t.Errorf("password saved in config file: %s", data)
+	}
+	if !strings.Contains(string(data), `"credsStore":"fake"`) {
+		t.Errorf("credentials store not recorded: %s", data)
+	}
+
+	user, pass, err := configFile.CredentialsStore.GetCredentials("10.1.5.249:5705")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user != "admin" || pass != "secret" {
+		t.Errorf("got %s/%s, expected admin/secret", user, pass)
+	}
+
+	if err := configFile.CredentialsStore.DeleteCredentials("10.1.5.249:5705"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewPasswordStore(fakeHelperName).Get("10.1.5.249:5705"); err != ErrNotFound {
+		t.Errorf("expected password to be erased, got %v", err)
+	}
+}
+
+func TestHelperStoreErrors(t *testing.T) {
+	defer installFakeHelper(t)()
+
+	err := NewPasswordStore(fakeHelperName).Store("host:5705", "admin", "fail")
+	if err == nil || !strings.Contains(err.Error(), "store refused") {
+		t.Errorf("expected helper error, got %v", err)
+	}
+
+	// erasing unknown hosts is fine
+	if err := NewPasswordStore(fakeHelperName).Erase("host:5705"); err != nil {
+		t.Errorf("unexpected error erasing unknown host: %v", err)
+	}
+
+	err = NewPasswordStore("missing").Store("host:5705", "admin", "secret")
+	if err == nil || !strings.Contains(err.Error(), "not found in PATH") {
+		t.Errorf("expected missing helper error, got %v", err)
+	}
+}
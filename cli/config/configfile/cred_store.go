@@ -18,31 +18,72 @@ func (c CredStore) GetCredentials(host string) (username string, password string
 	}
 
 	username = creds.Username
-	if creds.UseKeychain {
-		password, err = creds.passwordFromKeychain(host)
-	} else {
+	if store := creds.passwordStore(); store != nil {
+		password, err = store.Get(host)
+	} else if creds.Password != nil {
 		password = string(*creds.Password)
 	}
 
 	return
 }
 
+// SetCredentials stores the credentials of host, keeping the password in the
+// keychain on darwin and in the config file elsewhere.
 func (c CredStore) SetCredentials(host string, username string, password string) error {
-	pass := encodedPassword(password)
+	return c.setCredentials("", host, username, password)
+}
 
-	creds := credentials{
-		Username:    username,
-		Password:    &pass,
-		UseKeychain: runtime.GOOS == "darwin",
+// setCredentials stores the credentials of host, keeping the password in the
+// named store, if any.
+func (c CredStore) setCredentials(store string, host string, username string, password string) error {
+	if store == "" && runtime.GOOS == "darwin" {
+		store = keychainStoreName
 	}
 
-	c[host] = creds
-	if creds.UseKeychain {
-		return creds.saveToKeychain(host)
+	creds := credentials{Username: username}
+	switch store {
+	case "":
+		pass := encodedPassword(password)
+		creds.Password = &pass
+	case keychainStoreName:
+		creds.UseKeychain = true
+	default:
+		creds.CredsStore = store
+	}
+
+	if s := creds.passwordStore(); s != nil {
+		if err := s.Store(host, username, password); err != nil {
+			return err
+		}
 	}
+
+	// Drop the password from the store it was kept in before, and the
+	// session token of the previous login.
+	if old, ok := c[host]; ok {
+		if old.passwordStore() != nil && (old.UseKeychain != creds.UseKeychain || old.CredsStore != creds.CredsStore) {
+			old.passwordStore().Erase(host)
+		}
+		old.eraseToken(host)
+	}
+
+	c[host] = creds
 	return nil
 }
 
-func (c CredStore) DeleteCredentials(host string) {
+// DeleteCredentials forgets the credentials of host, erasing the password
+// and session token from the store holding them.
+func (c CredStore) DeleteCredentials(host string) error {
+	creds, ok := c[host]
+	if !ok {
+		return nil
+	}
+
 	delete(c, host)
+	tokenErr := creds.eraseToken(host)
+	if store := creds.passwordStore(); store != nil {
+		if err := store.Erase(host); err != nil {
+			return err
+		}
+	}
+	return tokenErr
 }
@@ -3,16 +3,14 @@ package configfile
 import (
 	"encoding/base64"
 	"encoding/json"
-	"os/exec"
-	"regexp"
-	"runtime"
-	"syscall"
 )
 
 type credentials struct {
 	Username    string           `json:"username"`
 	Password    *encodedPassword `json:"password,omitempty"`
 	UseKeychain bool             `json:"useKeychain,omitempty"`
+	CredsStore  string           `json:"credsStore,omitempty"`
+	Token       *sessionToken    `json:"token,omitempty"`
 }
 
 func (c credentials) MarshalJSON() ([]byte, error) {
@@ -20,60 +18,30 @@ func (c credentials) MarshalJSON() ([]byte, error) {
 		Username    string           `json:"username"`
 		Password    *encodedPassword `json:"password,omitempty"`
 		UseKeychain bool             `json:"useKeychain,omitempty"`
-	}{c.Username, c.Password, c.UseKeychain}
+		CredsStore  string           `json:"credsStore,omitempty"`
+		Token       *sessionToken    `json:"token,omitempty"`
+	}{c.Username, c.Password, c.UseKeychain, c.CredsStore, c.Token}
 
-	if internal.UseKeychain {
+	if internal.UseKeychain || internal.CredsStore != "" {
 		internal.Password = nil
+		if internal.Token != nil {
+			internal.Token = &sessionToken{Expires: internal.Token.Expires}
+		}
 	}
 
 	return json.Marshal(&internal)
 }
 
-func (c credentials) saveToKeychain(host string) error {
-	if runtime.GOOS != "darwin" {
-		return ErrNotDarwin
-	}
-
-	return exec.Command(
-		"/usr/bin/security", "add-generic-password",
-		"-s", "storageos_cli",
-		"-a", host,
-		"-w", string(*c.Password),
-		"-U",
-	).Run()
-}
-
-func (c credentials) passwordFromKeychain(host string) (string, error) {
-	if runtime.GOOS != "darwin" {
-		return "", ErrNotDarwin
-	}
-
-	com := exec.Command(
-		"/usr/bin/security", "find-generic-password",
-		"-s", "storageos_cli",
-		"-a", host,
-		"-g",
-	)
-
-	out, err := com.CombinedOutput()
-	exitCode := com.ProcessState.Sys().(syscall.WaitStatus).ExitStatus()
-
+// passwordStore returns the store holding the password, or nil if it is
+// kept in the config file.
+func (c credentials) passwordStore() PasswordStore {
 	switch {
-	case err != nil && exitCode == 44:
-		return "", ErrNotFound
-
-	case err != nil:
-		return "", err
-
-	default:
-		matches := regexp.MustCompile("password: \"(.+)\"").FindStringSubmatch(string(out))
-		if len(matches) != 2 {
-			return "", ErrNotFound
-		}
-
-		return matches[1], nil
+	case c.UseKeychain:
+		return keychainStore{}
+	case c.CredsStore != "":
+		return NewPasswordStore(c.CredsStore)
 	}
-
+	return nil
 }
 
 type encodedPassword string
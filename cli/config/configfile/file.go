@@ -6,22 +6,59 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // ConfigFile ~/.storageos/config.json file info
 type ConfigFile struct {
 	// HTTPHeaders          map[string]string           `json:"HttpHeaders,omitempty"`
-	CredentialsStore    CredStore `json:"knownHosts,omitempty"`
-	VolumesFormat       string    `json:"volumesFormat,omitempty"`
-	PoolsFormat         string    `json:"poolsFormat,omitempty"`
-	NamespacesFormat    string    `json:"namespacesFormat,omitempty"`
-	RulesFormat         string    `json:"rulesFormat,omitempty"`
-	UsersFormat         string    `json:"usersFormat,omitempty"`
-	PoliciesFormat      string    `json:"policiesFormat,omitempty"`
-	TemplatesFormat     string    `json:"templatesFormat,omitempty"`
-	ClusterHealthFormat string    `json:"clusterHealthFormat,omitempty"`
-	NodeHealthFormat    string    `json:"nodeHealthFormat,omitempty"`
-	Filename            string    `json:"-"` // Note: for internal use only
+	CredentialsStore CredStore `json:"knownHosts,omitempty"`
+	CredsStore       string    `json:"credsStore,omitempty"`
+	Formats
+	Contexts       map[string]*Context `json:"contexts,omitempty"`
+	CurrentContext string              `json:"currentContext,omitempty"`
+	// LastEndpoints holds the endpoint last used of each list of endpoints,
+	// keyed by the list, to be tried first next time.
+	LastEndpoints map[string]string `json:"lastEndpoints,omitempty"`
+	Filename      string            `json:"-"` // Note: for internal use only
+
+	// globalFormats holds the formats read from the file while the ones of a
+	// context are applied, so that they aren't saved.
+	globalFormats *Formats
+}
+
+// Formats are the default output formats of the list commands.
+type Formats struct {
+	VolumesFormat       string `json:"volumesFormat,omitempty"`
+	PoolsFormat         string `json:"poolsFormat,omitempty"`
+	NamespacesFormat    string `json:"namespacesFormat,omitempty"`
+	RulesFormat         string `json:"rulesFormat,omitempty"`
+	UsersFormat         string `json:"usersFormat,omitempty"`
+	PoliciesFormat      string `json:"policiesFormat,omitempty"`
+	TemplatesFormat     string `json:"templatesFormat,omitempty"`
+	EventsFormat        string `json:"eventsFormat,omitempty"`
+	ContextsFormat      string `json:"contextsFormat,omitempty"`
+	ClusterHealthFormat string `json:"clusterHealthFormat,omitempty"`
+	NodeHealthFormat    string `json:"nodeHealthFormat,omitempty"`
+}
+
+// LastEndpoint returns the endpoint last used of the endpoints, if any.
+func (configFile *ConfigFile) LastEndpoint(endpoints []string) string {
+	return configFile.LastEndpoints[strings.Join(endpoints, ",")]
+}
+
+// SetLastEndpoint records the endpoint last used of the endpoints.
+func (configFile *ConfigFile) SetLastEndpoint(endpoints []string, endpoint string) {
+	if configFile.LastEndpoints == nil {
+		configFile.LastEndpoints = make(map[string]string)
+	}
+	configFile.LastEndpoints[strings.Join(endpoints, ",")] = endpoint
+}
+
+// SetCredentials stores the credentials of host, keeping the password in the
+// credentials store set with credsStore, if any.
+func (configFile *ConfigFile) SetCredentials(host, username, password string) error {
+	return configFile.CredentialsStore.setCredentials(configFile.CredsStore, host, username, password)
 }
 
 // LoadFromReader reads the configuration data given and sets up the auth config
@@ -33,7 +70,11 @@ func (configFile *ConfigFile) LoadFromReader(configData io.Reader) error {
 // SaveToWriter encodes and writes out all the authorization information to
 // the given writer
 func (configFile *ConfigFile) SaveToWriter(writer io.Writer) error {
-	data, err := json.MarshalIndent(configFile, "", "\t")
+	saved := *configFile
+	if configFile.globalFormats != nil {
+		saved.Formats = *configFile.globalFormats
+	}
+	data, err := json.MarshalIndent(&saved, "", "\t")
 	if err != nil {
 		return err
 	}
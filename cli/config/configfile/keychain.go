@@ -0,0 +1,74 @@
+package configfile
+
+import (
+	"os/exec"
+	"regexp"
+	"runtime"
+	"syscall"
+)
+
+// keychainStoreName selects the macOS keychain, the default store on darwin.
+const keychainStoreName = "keychain"
+
+// keychainStore keeps passwords in the macOS keychain.
+type keychainStore struct{}
+
+func (keychainStore) Get(host string) (string, error) {
+	if runtime.GOOS != "darwin" {
+		return "", ErrNotDarwin
+	}
+
+	com := exec.Command(
+		"/usr/bin/security", "find-generic-password",
+		"-s", "storageos_cli",
+		"-a", host,
+		"-g",
+	)
+
+	out, err := com.CombinedOutput()
+	exitCode := com.ProcessState.Sys().(syscall.WaitStatus).ExitStatus()
+
+	switch {
+	case err != nil && exitCode == 44:
+		return "", ErrNotFound
+
+	case err != nil:
+		return "", err
+
+	default:
+		matches := regexp.MustCompile("password: \"(.+)\"").FindStringSubmatch(string(out))
+		if len(matches) != 2 {
+			return "", ErrNotFound
+		}
+
+		return matches[1], nil
+	}
+}
+
+func (keychainStore) Store(host, username, password string) error {
+	if runtime.GOOS != "darwin" {
+		return ErrNotDarwin
+	}
+
+	return exec.Command(
+		"/usr/bin/security", "add-generic-password",
+		"-s", "storageos_cli",
+		"-a", host,
+		"-w", password,
+		"-U",
+	).Run()
+}
+
+func (keychainStore) Erase(host string) error {
+	if runtime.GOOS != "darwin" {
+		return ErrNotDarwin
+	}
+
+	// Nothing to do if the password isn't there.
+	exec.Command(
+		"/usr/bin/security", "delete-generic-password",
+		"-s", "storageos_cli",
+		"-a", host,
+	).Run()
+	return nil
+}
@@ -0,0 +1,71 @@
+package configfile
+
+import (
+	"time"
+)
+
+// tokenKeySuffix tells the session token of a host apart from its password
+// in the credentials stores, which are keyed by host.
+const tokenKeySuffix = "/token"
+
+// sessionToken is an API token cached by login. Its value is kept along
+// with the password, in the config file or in the credentials store.
+type sessionToken struct {
+	Value   *encodedPassword `json:"value,omitempty"`
+	Expires time.Time        `json:"expires"`
+}
+
+// GetToken returns the session token cached for host, and when it expires.
+// A zero expiry means the token's lifetime isn't known.
+func (c CredStore) GetToken(host string) (token string, expires time.Time, err error) {
+	creds, ok := c[host]
+	if !ok {
+		return "", time.Time{}, ErrUnknownHost
+	}
+	if creds.Token == nil {
+		return "", time.Time{}, ErrNotFound
+	}
+
+	if store := creds.passwordStore(); store != nil {
+		token, err = store.Get(host + tokenKeySuffix)
+	} else if creds.Token.Value != nil {
+		token = string(*creds.Token.Value)
+	}
+	if err == nil && token == "" {
+		err = ErrNotFound
+	}
+	return token, creds.Token.Expires, err
+}
+
+// SetToken caches the session token of host, which must have credentials.
+func (c CredStore) SetToken(host string, token string, expires time.Time) error {
+	creds, ok := c[host]
+	if !ok {
+		return ErrUnknownHost
+	}
+
+	t := &sessionToken{Expires: expires}
+	if store := creds.passwordStore(); store != nil {
+		if err := store.Store(host+tokenKeySuffix, creds.Username, token); err != nil {
+			return err
+		}
+	} else {
+		value := encodedPassword(token)
+		t.Value = &value
+	}
+
+	creds.Token = t
+	c[host] = creds
+	return nil
+}
+
+// eraseToken removes the session token of creds from the store holding it.
+func (creds credentials) eraseToken(host string) error {
+	if creds.Token == nil {
+		return nil
+	}
+	if store := creds.passwordStore(); store != nil {
+		return store.Erase(host + tokenKeySuffix)
+	}
+	return nil
+}
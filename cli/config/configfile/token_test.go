@@ -0,0 +1,79 @@
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionToken(t *testing.T) {
+	expires := time.Unix(1700000000, 0).UTC()
+
+	for _, store := range []string{"", fakeHelperName} {
+		func() {
+			defer installFakeHelper(t)()
+
+			configFile := &ConfigFile{CredentialsStore: make(CredStore), CredsStore: store}
+
+			if err := configFile.CredentialsStore.SetToken("host:5705", "tok", expires); err != ErrUnknownHost {
+				t.Errorf("%q: expected ErrUnknownHost without credentials, got %v", store, err)
+			}
+
+			if err := configFile.SetCredentials("host:5705", "admin", "secret"); err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := configFile.CredentialsStore.GetToken("host:5705"); err != ErrNotFound {
+				t.Errorf("%q: expected ErrNotFound before login, got %v", store, err)
+			}
+
+			if err := configFile.CredentialsStore.SetToken("host:5705", "tok", expires); err != nil {
+				t.Fatal(err)
+			}
+
+			// The token goes wherever the password does, but its expiry is
+			// always in the config file.
+			var buf bytes.Buffer
+			if err := configFile.SaveToWriter(&buf); err != nil {
+				t.Fatal(err)
+			}
+			loaded := &ConfigFile{}
+			if err := loaded.LoadFromReader(&buf); err != nil {
+				t.Fatal(err)
+			}
+			data, _ := json.Marshal(loaded.CredentialsStore)
+			if inFile := strings.Contains(string(data), `"value"`); inFile != (store == "") {
+				t.Errorf("%q: unexpected token value in config file: %s", store, data)
+			}
+
+			token, exp, err := loaded.CredentialsStore.GetToken("host:5705")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if token != "tok" || !exp.Equal(expires) {
+				t.Errorf("%q: got %s expiring %v, expected tok expiring %v", store, token, exp, expires)
+			}
+
+			// Logging in again drops the previous session.
+			if err := loaded.SetCredentials("host:5705", "admin", "secret"); err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := loaded.CredentialsStore.GetToken("host:5705"); err != ErrNotFound {
+				t.Errorf("%q: expected token dropped on login, got %v", store, err)
+			}
+
+			if err := loaded.CredentialsStore.SetToken("host:5705", "tok2", time.Time{}); err != nil {
+				t.Fatal(err)
+			}
+			if err := loaded.CredentialsStore.DeleteCredentials("host:5705"); err != nil {
+				t.Fatal(err)
+			}
+			if store != "" {
+				if _, err := NewPasswordStore(store).Get("host:5705" + tokenKeySuffix); err != ErrNotFound {
+					t.Errorf("expected token erased on logout, got %v", err)
+				}
+			}
+		}()
+	}
+}
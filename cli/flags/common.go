@@ -3,6 +3,7 @@ package flags
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/docker/go-connections/tlsconfig"
 	"github.com/sirupsen/logrus"
@@ -23,24 +24,34 @@ const (
 	DefaultCertFile = "cert.pem"
 	// FlagTLSVerify is the flag name for the TLS verification option
 	FlagTLSVerify = "tlsverify"
+	// FlagTLSInsecure is the flag name for the option skipping TLS
+	// verification
+	FlagTLSInsecure = "tls-insecure"
 )
 
 var (
-	storageosCertPath  = os.Getenv("DOCKER_CERT_PATH")
-	storageosTLSVerify = os.Getenv("DOCKER_TLS_VERIFY") != ""
+	storageosCertPath  = os.Getenv(cliconfig.EnvStorageOSCertPath)
+	storageosTLSVerify = os.Getenv(cliconfig.EnvStorageOSTLSVerify) != ""
 )
 
 // CommonOptions are options common to both the client and the daemon.
 type CommonOptions struct {
-	Debug      bool
-	Hosts      []string
-	Username   string
-	Password   string
-	LogLevel   string
-	TLS        bool
-	TLSVerify  bool
-	TLSOptions *tlsconfig.Options
-	TrustKey   string
+	Debug       bool
+	Hosts       []string
+	Context     string
+	Username    string
+	Password    string
+	LogLevel    string
+	TLS         bool
+	TLSVerify   bool
+	TLSInsecure bool
+	TLSOptions  *tlsconfig.Options
+	TrustKey    string
+
+	// NoContext is set for the commands managing contexts, which use the
+	// name of the current context but not its settings, so that a broken
+	// context can still be fixed or removed.
+	NoContext bool
 }
 
 // NewCommonOptions returns a new CommonOptions
@@ -56,24 +67,27 @@ func (commonOpts *CommonOptions) InstallFlags(flags *pflag.FlagSet) {
 
 	flags.BoolVarP(&commonOpts.Debug, "debug", "D", false, "Enable debug mode")
 	// flags.StringVarP(&commonOpts.LogLevel, "log-level", "l", "info", `Set the logging level ("debug"|"info"|"warn"|"error"|"fatal")`)
-	// flags.BoolVar(&commonOpts.TLS, "tls", false, "Use TLS; implied by --tlsverify")
-	// flags.BoolVar(&commonOpts.TLSVerify, FlagTLSVerify, storageosTLSVerify, "Use TLS and verify the remote")
+	flags.BoolVar(&commonOpts.TLS, "tls", false, "Use TLS, verifying the remote unless --tls-insecure is given; implied by the other TLS flags")
+	flags.BoolVar(&commonOpts.TLSVerify, FlagTLSVerify, storageosTLSVerify, fmt.Sprintf("Use TLS and verify the remote (default from %s env variable)", cliconfig.EnvStorageOSTLSVerify))
+	flags.BoolVar(&commonOpts.TLSInsecure, FlagTLSInsecure, false, "Use TLS without verifying the remote")
 
-	// // TODO use flag flags.String("identity"}, "i"	, "", "Path to libtrust key file")
+	// TODO use flag flags.String("identity"}, "i"	, "", "Path to libtrust key file")
 
-	// commonOpts.TLSOptions = &tlsconfig.Options{
-	// 	CAFile:   filepath.Join(storageosCertPath, DefaultCaFile),
-	// 	CertFile: filepath.Join(storageosCertPath, DefaultCertFile),
-	// 	KeyFile:  filepath.Join(storageosCertPath, DefaultKeyFile),
-	// }
-	// tlsOptions := commonOpts.TLSOptions
-	// flags.Var(opts.NewQuotedString(&tlsOptions.CAFile), "tlscacert", "Trust certs signed only by this CA")
-	// flags.Var(opts.NewQuotedString(&tlsOptions.CertFile), "tlscert", "Path to TLS certificate file")
-	// flags.Var(opts.NewQuotedString(&tlsOptions.KeyFile), "tlskey", "Path to TLS key file")
+	commonOpts.TLSOptions = &tlsconfig.Options{
+		CAFile:   filepath.Join(storageosCertPath, DefaultCaFile),
+		CertFile: filepath.Join(storageosCertPath, DefaultCertFile),
+		KeyFile:  filepath.Join(storageosCertPath, DefaultKeyFile),
+	}
+	tlsOptions := commonOpts.TLSOptions
+	flags.Var(opts.NewQuotedString(&tlsOptions.CAFile), "tlscacert", "Trust certs signed only by this CA")
+	flags.Var(opts.NewQuotedString(&tlsOptions.CertFile), "tlscert", "Path to TLS certificate file")
+	flags.Var(opts.NewQuotedString(&tlsOptions.KeyFile), "tlskey", "Path to TLS key file")
 
 	hostOpt := opts.NewNamedListOptsRef("hosts", &commonOpts.Hosts, opts.ValidateHost)
 	flags.VarP(hostOpt, "host", "H", fmt.Sprintf("Node endpoint(s) to connect to (will override %s env variable value)", cliconfig.EnvStorageOSHost))
 
+	flags.StringVar(&commonOpts.Context, "context", "", fmt.Sprintf("Name of the context to use (will override %s env variable value and the current context)", cliconfig.EnvStorageOSContext))
+
 	flags.StringVarP(&commonOpts.Username, "username", "u", "", fmt.Sprintf(`API username (will override %s env variable value)`, cliconfig.EnvStorageosUsername))
 	flags.StringVarP(&commonOpts.Password, "password", "p", "", fmt.Sprintf(`API password (will override %s env variable value)`, cliconfig.EnvStorageosPassword))
 }
@@ -83,20 +97,34 @@ func (commonOpts *CommonOptions) InstallFlags(flags *pflag.FlagSet) {
 func (commonOpts *CommonOptions) SetDefaultOptions(flags *pflag.FlagSet) {
 	// Regardless of whether the user sets it to true or false, if they
 	// specify --tlsverify at all then we need to turn on TLS
-	// TLSVerify can be true even if not set due to DOCKER_TLS_VERIFY env var, so we need
+	// TLSVerify can be true even if not set due to STORAGEOS_TLS_VERIFY env var, so we need
 	// to check that here as well
-	if flags.Changed(FlagTLSVerify) || commonOpts.TLSVerify {
+	if flags.Changed(FlagTLSVerify) || commonOpts.TLSVerify || commonOpts.TLSInsecure {
 		commonOpts.TLS = true
 	}
+	// Giving any of the TLS files also means TLS is wanted.
+	for _, name := range []string{"tlscacert", "tlscert", "tlskey"} {
+		if flags.Changed(name) {
+			commonOpts.TLS = true
+		}
+	}
 
 	if !commonOpts.TLS {
 		commonOpts.TLSOptions = nil
 	} else {
+		// The remote is always verified, against the given CA or else the
+		// system roots, unless explicitly skipped.
 		tlsOptions := commonOpts.TLSOptions
-		tlsOptions.InsecureSkipVerify = !commonOpts.TLSVerify
-
-		// Reset CertFile and KeyFile to empty string if the user did not specify
-		// the respective flags and the respective default files were not found.
+		tlsOptions.InsecureSkipVerify = commonOpts.TLSInsecure
+
+		// Reset CAFile, CertFile and KeyFile to empty string if the user did not
+		// specify the respective flags and the respective default files were not
+		// found. Without a CA file the system roots are used.
+		if !flags.Changed("tlscacert") {
+			if _, err := os.Stat(tlsOptions.CAFile); os.IsNotExist(err) {
+				tlsOptions.CAFile = ""
+			}
+		}
 		if !flags.Changed("tlscert") {
 			if _, err := os.Stat(tlsOptions.CertFile); os.IsNotExist(err) {
 				tlsOptions.CertFile = ""
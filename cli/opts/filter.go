@@ -0,0 +1,98 @@
+package opts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FilterOpt is a flag type collecting key=value filters. Filters may be given
+// by repeating the flag or as a comma separated list. Values for the same key
+// are OR'd together while different keys are AND'd.
+type FilterOpt struct {
+	filter map[string][]string
+}
+
+// NewFilterOpt returns a new, empty FilterOpt.
+func NewFilterOpt() FilterOpt {
+	return FilterOpt{filter: make(map[string][]string)}
+}
+
+// Set parses one or more comma separated key=value pairs and adds them to the
+// filter.
+func (o *FilterOpt) Set(value string) error {
+	if o.filter == nil {
+		o.filter = make(map[string][]string)
+	}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return fmt.Errorf("bad format of filter %q (expected key=value)", pair)
+		}
+		key := strings.ToLower(strings.TrimSpace(kv[0]))
+		o.filter[key] = append(o.filter[key], strings.TrimSpace(kv[1]))
+	}
+	return nil
+}
+
+func (o *FilterOpt) String() string {
+	var pairs []string
+	for k, values := range o.filter {
+		for _, v := range values {
+			pairs = append(pairs, k+"="+v)
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Type returns the option type
+func (o *FilterOpt) Type() string {
+	return "filter"
+}
+
+// Value returns the filter as a map of keys to the accepted values.
+func (o *FilterOpt) Value() map[string][]string {
+	return o.filter
+}
+
+// Len returns the number of keys in the filter.
+func (o *FilterOpt) Len() int {
+	return len(o.filter)
+}
+
+// Validate returns an error if the filter uses a key that is not in keys.
+func (o *FilterOpt) Validate(keys ...string) error {
+	for k := range o.filter {
+		valid := false
+		for _, key := range keys {
+			if k == key {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("invalid filter key %q.  Must be one of %q", k, keys)
+		}
+	}
+	return nil
+}
+
+// Match returns true if value is accepted by the filter for key. A key that
+// has no filter accepts every value.
+func (o *FilterOpt) Match(key, value string) bool {
+	values, ok := o.filter[key]
+	if !ok {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package opts
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterOptSet(t *testing.T) {
+	filter := NewFilterOpt()
+
+	for _, value := range []string{"action=create,target=vol1", "Action=delete", "status=failed"} {
+		if err := filter.Set(value); err != nil {
+			t.Fatalf("unexpected error setting %q: %v", value, err)
+		}
+	}
+
+	expected := map[string][]string{
+		"action": {"create", "delete"},
+		"target": {"vol1"},
+		"status": {"failed"},
+	}
+	if !reflect.DeepEqual(filter.Value(), expected) {
+		t.Fatalf("expected %v, got %v", expected, filter.Value())
+	}
+
+	if s := filter.String(); s != "action=create,action=delete,status=failed,target=vol1" {
+		t.Fatalf("unexpected string representation %q", s)
+	}
+}
+
+func TestFilterOptSetInvalid(t *testing.T) {
+	for _, value := range []string{"action", "=create", "action=", "action=create,target"} {
+		filter := NewFilterOpt()
+		if err := filter.Set(value); err == nil {
+			t.Errorf("expected error for %q", value)
+		}
+	}
+}
+
+func TestFilterOptMatch(t *testing.T) {
+	filter := NewFilterOpt()
+	filter.Set("action=create,action=delete")
+
+	fixtures := []struct {
+		key      string
+		value    string
+		expected bool
+	}{
+		{"action", "create", true},
+		{"action", "delete", true},
+		{"action", "update", false},
+		{"target", "anything", true},
+	}
+
+	for _, fix := range fixtures {
+		if got := filter.Match(fix.key, fix.value); got != fix.expected {
+			t.Errorf("Match(%q, %q): expected %v, got %v", fix.key, fix.value, fix.expected, got)
+		}
+	}
+}
+
+func TestFilterOptValidate(t *testing.T) {
+	filter := NewFilterOpt()
+	filter.Set("action=create,target=vol1")
+
+	if err := filter.Validate("action", "target", "status"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := filter.Validate("action"); err == nil {
+		t.Fatal("expected error for unsupported key")
+	}
+}
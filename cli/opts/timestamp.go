@@ -0,0 +1,43 @@
+package opts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// ParseTimestamp parses a point in time given as a duration relative to now
+// (e.g. 10m), a Unix timestamp in seconds or an RFC 3339 date. Dates without
+// a zone are taken to be in local time.
+func ParseTimestamp(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+
+	if secs, err := strconv.ParseFloat(value, 64); err == nil {
+		whole := int64(secs)
+		return time.Unix(whole, int64((secs-float64(whole))*float64(time.Second))), nil
+	}
+
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid timestamp %q: use a duration (10m), a Unix timestamp or an RFC 3339 date", value)
+}
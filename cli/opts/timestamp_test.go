@@ -0,0 +1,39 @@
+package opts
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	now := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	fixtures := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"", time.Time{}},
+		{"10m", now.Add(-10 * time.Minute)},
+		{"1h30m", now.Add(-90 * time.Minute)},
+		{"1496318400", time.Unix(1496318400, 0)},
+		{"1496318400.5", time.Unix(1496318400, 500000000)},
+		{"2017-06-01T10:00:00Z", time.Date(2017, 6, 1, 10, 0, 0, 0, time.UTC)},
+		{"2017-06-01T10:00:00+02:00", time.Date(2017, 6, 1, 8, 0, 0, 0, time.UTC)},
+		{"2017-06-01", time.Date(2017, 6, 1, 0, 0, 0, 0, time.Local)},
+	}
+
+	for _, fix := range fixtures {
+		got, err := ParseTimestamp(fix.value, now)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", fix.value, err)
+			continue
+		}
+		if !got.Equal(fix.expected) {
+			t.Errorf("%q: expected %v, got %v", fix.value, fix.expected, got)
+		}
+	}
+
+	if _, err := ParseTimestamp("yesterday", now); err == nil {
+		t.Error("expected error for invalid timestamp")
+	}
+}
@@ -56,6 +56,7 @@ func newStorageOSCommand(storageosCli *command.StorageOSCli) *cobra.Command {
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			// flags must be the top-level command flags, not cmd.Flags()
 			opts.Common.SetDefaultOptions(flags)
+			opts.Common.NoContext = !command.UsesContext(cmd)
 			preRun(opts)
 			if err := storageosCli.Initialize(opts); err != nil {
 				return err
@@ -178,7 +179,9 @@ func main() {
 	storageosCli := command.NewStorageOSCli(stdin, stdout, stderr)
 	cmd := newStorageOSCommand(storageosCli)
 
-	if err := cmd.Execute(); err != nil {
+	err := cmd.Execute()
+	storageosCli.SaveLastEndpoint()
+	if err != nil {
 		if sterr, ok := err.(cli.StatusError); ok {
 			if sterr.Status != "" {
 				fmt.Fprintln(stderr, sterr.Status)
@@ -0,0 +1,366 @@
+// Package jsonpath implements the subset of JSONPath templates supported by
+// kubectl, for use on decoded JSON documents.
+//
+// A template is text with expressions between braces, such as
+// {.items[*].name}. Expressions are paths made of fields (.name or
+// ['name']), indexes ([0], [-1]), slices ([1:3]) and wildcards ([*] or .*),
+// quoted strings ({"\n"}), or {range PATH} ... {end} blocks evaluating their
+// body against each value of PATH.
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Template is a parsed JSONPath template.
+type Template struct {
+	nodes []node
+}
+
+type nodeKind int
+
+const (
+	textNode nodeKind = iota
+	pathNode
+	rangeNode
+)
+
+// node is a piece of template: literal text, a path or a range block.
+type node struct {
+	kind nodeKind
+	text string
+	path []step
+	body []node
+}
+
+// step selects values from each of the values of the previous one.
+type step struct {
+	field    string
+	wildcard bool
+	index    *int
+	slice    *[2]*int
+}
+
+// Parse parses a template.
+func Parse(text string) (*Template, error) {
+	p := &parser{text: text}
+	nodes, err := p.parse(false)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{nodes: nodes}, nil
+}
+
+type parser struct {
+	text string
+	pos  int
+}
+
+// parse parses nodes until the end of the text, or of the range block
+// being parsed when inRange is set.
+func (p *parser) parse(inRange bool) ([]node, error) {
+	var nodes []node
+	for p.pos < len(p.text) {
+		open := strings.IndexByte(p.text[p.pos:], '{')
+		if open < 0 {
+			nodes = append(nodes, node{kind: textNode, text: unescape(p.text[p.pos:])})
+			p.pos = len(p.text)
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, node{kind: textNode, text: unescape(p.text[p.pos : p.pos+open])})
+		}
+		p.pos += open + 1
+
+		end := p.closingBrace()
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed action in %q", p.text)
+		}
+		expr := strings.TrimSpace(p.text[p.pos:end])
+		p.pos = end + 1
+
+		switch {
+		case expr == "end":
+			if !inRange {
+				return nil, fmt.Errorf("unexpected {end} in %q", p.text)
+			}
+			return nodes, nil
+		case strings.HasPrefix(expr, "range "):
+			path, err := parsePath(strings.TrimSpace(strings.TrimPrefix(expr, "range ")))
+			if err != nil {
+				return nil, err
+			}
+			body, err := p.parse(true)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node{kind: rangeNode, path: path, body: body})
+		case strings.HasPrefix(expr, `"`):
+			s, err := strconv.Unquote(expr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid string %s: %v", expr, err)
+			}
+			nodes = append(nodes, node{kind: textNode, text: s})
+		default:
+			path, err := parsePath(expr)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node{kind: pathNode, path: path})
+		}
+	}
+	if inRange {
+		return nil, fmt.Errorf("missing {end} in %q", p.text)
+	}
+	return nodes, nil
+}
+
+// closingBrace returns the position of the brace closing the action
+// starting at p.pos, skipping over quoted strings.
+func (p *parser) closingBrace() int {
+	quote := byte(0)
+	for i := p.pos; i < len(p.text); i++ {
+		c := p.text[i]
+		switch {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		case quote == 0 && c == '}':
+			return i
+		}
+	}
+	return -1
+}
+
+func unescape(text string) string {
+	return strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(text)
+}
+
+// parsePath parses a path such as .items[*].name. The leading $ or @ of
+// the current object is optional.
+func parsePath(expr string) ([]step, error) {
+	s := strings.TrimPrefix(strings.TrimPrefix(expr, "$"), "@")
+	if s == "" || s == "." {
+		return nil, nil
+	}
+
+	var steps []step
+	for len(s) > 0 {
+		switch s[0] {
+		case '.':
+			if strings.HasPrefix(s, "..") {
+				return nil, fmt.Errorf("recursive descent is not supported in %q", expr)
+			}
+			s = s[1:]
+			n := strings.IndexAny(s, ".[")
+			if n < 0 {
+				n = len(s)
+			}
+			name := s[:n]
+			s = s[n:]
+			switch name {
+			case "":
+				return nil, fmt.Errorf("empty field name in %q", expr)
+			case "*":
+				steps = append(steps, step{wildcard: true})
+			default:
+				steps = append(steps, step{field: name})
+			}
+		case '[':
+			n := strings.IndexByte(s, ']')
+			if n < 0 {
+				return nil, fmt.Errorf("unclosed [ in %q", expr)
+			}
+			st, err := parseSubscript(s[1:n])
+			if err != nil {
+				return nil, fmt.Errorf("%v in %q", err, expr)
+			}
+			steps = append(steps, st)
+			s = s[n+1:]
+		default:
+			return nil, fmt.Errorf("unexpected %q in %q, paths start with a dot", s[0], expr)
+		}
+	}
+	return steps, nil
+}
+
+func parseSubscript(sub string) (step, error) {
+	sub = strings.TrimSpace(sub)
+	switch {
+	case sub == "*":
+		return step{wildcard: true}, nil
+	case strings.HasPrefix(sub, "?"):
+		return step{}, fmt.Errorf("filters are not supported")
+	case len(sub) >= 2 && (sub[0] == '\'' || sub[0] == '"') && sub[len(sub)-1] == sub[0]:
+		return step{field: sub[1 : len(sub)-1]}, nil
+	case strings.Contains(sub, ":"):
+		parts := strings.Split(sub, ":")
+		if len(parts) != 2 {
+			return step{}, fmt.Errorf("invalid slice [%s]", sub)
+		}
+		var bounds [2]*int
+		for i, part := range parts {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return step{}, fmt.Errorf("invalid slice [%s]", sub)
+			}
+			bounds[i] = &n
+		}
+		return step{slice: &bounds}, nil
+	default:
+		n, err := strconv.Atoi(sub)
+		if err != nil {
+			return step{}, fmt.Errorf("invalid index [%s]", sub)
+		}
+		return step{index: &n}, nil
+	}
+}
+
+// Execute writes the template applied to data, a decoded JSON document.
+// Paths matching several values print them separated by spaces, and paths
+// matching none print nothing.
+func (t *Template) Execute(w io.Writer, data interface{}) error {
+	var buf bytes.Buffer
+	if err := execute(&buf, t.nodes, data); err != nil {
+		return err
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func execute(buf *bytes.Buffer, nodes []node, data interface{}) error {
+	for _, n := range nodes {
+		if n.kind == textNode {
+			buf.WriteString(n.text)
+			continue
+		}
+
+		values := evaluate(n.path, data)
+		if n.kind == rangeNode {
+			for _, v := range values {
+				if err := execute(buf, n.body, v); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		for i, v := range values {
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+			if err := printValue(buf, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func evaluate(path []step, data interface{}) []interface{} {
+	values := []interface{}{data}
+	for _, st := range path {
+		var next []interface{}
+		for _, v := range values {
+			next = append(next, st.apply(v)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func (st step) apply(v interface{}) []interface{} {
+	switch {
+	case st.wildcard:
+		switch v := v.(type) {
+		case []interface{}:
+			return v
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			values := make([]interface{}, len(keys))
+			for i, k := range keys {
+				values[i] = v[k]
+			}
+			return values
+		}
+	case st.index != nil:
+		if l, ok := v.([]interface{}); ok {
+			i := *st.index
+			if i < 0 {
+				i += len(l)
+			}
+			if i >= 0 && i < len(l) {
+				return []interface{}{l[i]}
+			}
+		}
+	case st.slice != nil:
+		if l, ok := v.([]interface{}); ok {
+			start, end := bound(st.slice[0], 0, len(l)), bound(st.slice[1], len(l), len(l))
+			if start < end {
+				return l[start:end]
+			}
+		}
+	default:
+		if m, ok := v.(map[string]interface{}); ok {
+			if field, ok := m[st.field]; ok {
+				return []interface{}{field}
+			}
+		}
+	}
+	return nil
+}
+
+// bound returns the slice bound b within a list of length n, or def if
+// it isn't set.
+func bound(b *int, def, n int) int {
+	if b == nil {
+		return def
+	}
+	i := *b
+	if i < 0 {
+		i += n
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > n {
+		return n
+	}
+	return i
+}
+
+func printValue(buf *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+	case string:
+		buf.WriteString(v)
+	case json.Number:
+		buf.WriteString(v.String())
+	case float64:
+		buf.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	}
+	return nil
+}
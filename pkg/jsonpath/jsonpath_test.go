@@ -0,0 +1,84 @@
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testDoc = `{
+	"kind": "VolumeList",
+	"items": [
+		{"name": "a", "size": 5, "labels": {"app": "db", "tier": "1"}, "replicas": []},
+		{"name": "b", "size": 10, "labels": {"app": "web"}, "inode": true},
+		{"name": "c", "size": 20}
+	]
+}`
+
+func TestExecute(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(testDoc))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{template: "{.kind}", want: "VolumeList"},
+		{template: "{$.kind}", want: "VolumeList"},
+		{template: "{.items[*].name}", want: "a b c"},
+		{template: "{.items[0].name}", want: "a"},
+		{template: "{.items[-1].name}", want: "c"},
+		{template: "{.items[5].name}", want: ""},
+		{template: "{.items[1:].size}", want: "10 20"},
+		{template: "{.items[:2].name}", want: "a b"},
+		{template: "{.items[0].labels.*}", want: "db 1"},
+		{template: "{.items[0].labels['app']}", want: "db"},
+		{template: "{.items[*].labels.app}", want: "db web"},
+		{template: "{.items[1].inode}", want: "true"},
+		{template: "{.items[0].labels}", want: `{"app":"db","tier":"1"}`},
+		{template: "{.items[0].replicas}", want: "[]"},
+		{template: "{.missing}", want: ""},
+		{template: `{range .items[*]}{.name}={.size}{"\n"}{end}`, want: "a=5\nb=10\nc=20\n"},
+		{template: `{range .items[*]}{.name}\t{@.size}\n{end}`, want: "a\t5\nb\t10\nc\t20\n"},
+		{template: "names: {.items[*].name}!", want: "names: a b c!"},
+	}
+	for _, tt := range tests {
+		tmpl, err := Parse(tt.template)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.template, err)
+			continue
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, doc); err != nil {
+			t.Errorf("Execute(%q): %v", tt.template, err)
+			continue
+		}
+		if buf.String() != tt.want {
+			t.Errorf("Execute(%q) = %q, want %q", tt.template, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, template := range []string{
+		"{.items",
+		"{end}",
+		"{range .items[*]}{.name}",
+		"{items}",
+		"{.items[}",
+		"{.items[x]}",
+		"{.items[1:2:3]}",
+		"{..name}",
+		"{.items[?(@.size > 5)]}",
+		`{"unterminated}`,
+	} {
+		if _, err := Parse(template); err == nil {
+			t.Errorf("Parse(%q): expected an error", template)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package manifest
+
+import (
+	"sort"
+
+	"github.com/storageos/go-api/types"
+)
+
+// FromNamespace describes a namespace.
+func FromNamespace(ns *types.Namespace) *Object {
+	return &Object{
+		APIVersion: APIVersion,
+		Kind:       NamespaceKind,
+		Metadata:   Metadata{Name: ns.Name, Labels: ns.Labels},
+		Spec: &NamespaceSpec{
+			DisplayName: ns.DisplayName,
+			Description: ns.Description,
+		},
+	}
+}
+
+// FromPool describes a capacity pool.
+func FromPool(pool *types.Pool) *Object {
+	return &Object{
+		APIVersion: APIVersion,
+		Kind:       PoolKind,
+		Metadata:   Metadata{Name: pool.Name, Labels: pool.Labels},
+		Spec: &PoolSpec{
+			Description:     pool.Description,
+			Default:         pool.Default,
+			DefaultDriver:   pool.DefaultDriver,
+			ControllerNames: sorted(pool.ControllerNames),
+			DriverNames:     sorted(pool.DriverNames),
+			Active:          pool.Active,
+		},
+	}
+}
+
+// FromTemplate describes a name template.
+func FromTemplate(tmpl *types.Template) *Object {
+	return &Object{
+		APIVersion: APIVersion,
+		Kind:       TemplateKind,
+		Metadata:   Metadata{Name: tmpl.Name},
+		Spec: &TemplateSpec{
+			Description:   tmpl.Description,
+			Format:        tmpl.Format,
+			AutoIncrement: tmpl.AutoIncrement,
+			Padding:       tmpl.Padding,
+			PaddingLength: tmpl.PaddingLength,
+			Active:        tmpl.Active,
+			Weight:        tmpl.Weight,
+			ObjectTypes:   tmpl.ObjectTypes,
+			Labels:        tmpl.Labels,
+		},
+	}
+}
+
+// FromRule describes a rule.
+func FromRule(rule *types.Rule) *Object {
+	return &Object{
+		APIVersion: APIVersion,
+		Kind:       RuleKind,
+		Metadata:   Metadata{Name: rule.Name, Namespace: rule.Namespace},
+		Spec: &RuleSpec{
+			Description: rule.Description,
+			Active:      rule.Active,
+			Weight:      rule.Weight,
+			Action:      rule.RuleAction,
+			Selector:    rule.Selector,
+			Labels:      rule.Labels,
+		},
+	}
+}
+
+// FromUser describes a user, leaving out the password.
+func FromUser(user *types.User) *Object {
+	var groups []string
+	for _, g := range user.Groups {
+		if g != "" {
+			groups = append(groups, g)
+		}
+	}
+	sort.Strings(groups)
+
+	return &Object{
+		APIVersion: APIVersion,
+		Kind:       UserKind,
+		Metadata:   Metadata{Name: user.Username},
+		Spec: &UserSpec{
+			Groups: groups,
+			Role:   user.Role,
+		},
+	}
+}
+
+// FromPolicy describes a policy.
+func FromPolicy(policy types.Policy) *Object {
+	spec := PolicySpec(policy.Spec)
+	return &Object{
+		APIVersion: APIVersion,
+		Kind:       PolicyKind,
+		Spec:       &spec,
+	}
+}
+
+// FromVolume describes a volume, leaving out its runtime state.
+func FromVolume(vol *types.Volume) *Object {
+	return &Object{
+		APIVersion: APIVersion,
+		Kind:       VolumeKind,
+		Metadata:   Metadata{Name: vol.Name, Namespace: vol.Namespace, Labels: vol.Labels},
+		Spec: &VolumeSpec{
+			Description:  vol.Description,
+			Size:         vol.Size,
+			Pool:         vol.Pool,
+			FSType:       vol.FSType,
+			NodeSelector: vol.NodeSelector,
+		},
+	}
+}
+
+// Policy returns the policy described by a Policy object.
+func (p *PolicySpec) Policy() types.Policy {
+	var policy types.Policy
+	policy.Spec = struct {
+		User            string `json:"user,omitempty"`
+		Group           string `json:"group,omitempty"`
+		Readonly        bool   `json:"readonly,omitempty"`
+		APIGroup        string `json:"apiGroup,omitempty"`
+		Resource        string `json:"resource,omitempty"`
+		Namespace       string `json:"namespace,omitempty"`
+		NonResourcePath string `json:"nonResourcePath,omitempty"`
+	}(*p)
+	return policy
+}
+
+// Complete returns a copy of desired with the fields it leaves for the
+// server to choose taken from live, so that the two can be compared.
+//
+// Volume labels are merged with the live ones rather than replacing them,
+// as rules add labels of their own.
+func Complete(desired, live *Object) *Object {
+	o := *desired
+	switch spec := desired.Spec.(type) {
+	case *NamespaceSpec:
+		s := *spec
+		if s.DisplayName == "" {
+			s.DisplayName = live.Spec.(*NamespaceSpec).DisplayName
+		}
+		o.Spec = &s
+	case *PoolSpec:
+		s := *spec
+		if s.DefaultDriver == "" {
+			s.DefaultDriver = live.Spec.(*PoolSpec).DefaultDriver
+		}
+		o.Spec = &s
+	case *UserSpec:
+		// The password is only used to create the user.
+		s := *spec
+		s.Password = ""
+		o.Spec = &s
+	case *VolumeSpec:
+		s := *spec
+		l := live.Spec.(*VolumeSpec)
+		if s.Pool == "" {
+			s.Pool = l.Pool
+		}
+		if s.FSType == "" {
+			s.FSType = l.FSType
+		}
+		o.Spec = &s
+
+		labels := make(map[string]string)
+		for k, v := range live.Metadata.Labels {
+			labels[k] = v
+		}
+		for k, v := range desired.Metadata.Labels {
+			labels[k] = v
+		}
+		o.Metadata.Labels = labels
+	}
+	return &o
+}
+
+func sorted(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	c := append([]string(nil), s...)
+	sort.Strings(c)
+	return c
+}
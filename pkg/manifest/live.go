@@ -0,0 +1,159 @@
+package manifest
+
+import (
+	"fmt"
+
+	api "github.com/storageos/go-api"
+	"github.com/storageos/go-api/types"
+)
+
+// List returns the live objects of the given kind. Objects of namespaced
+// kinds are listed in each of namespaces.
+func List(client *api.Client, kind string, namespaces []string) ([]*Object, error) {
+	var objects []*Object
+	switch kind {
+	case NamespaceKind:
+		list, err := client.NamespaceList(types.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range list {
+			objects = append(objects, withID(FromNamespace(ns), ns.ID))
+		}
+	case PoolKind:
+		list, err := client.PoolList(types.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, pool := range list {
+			objects = append(objects, withID(FromPool(pool), pool.ID))
+		}
+	case TemplateKind:
+		list, err := client.TemplateList(types.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range list {
+			objects = append(objects, withID(FromTemplate(&list[i]), list[i].ID))
+		}
+	case RuleKind:
+		for _, namespace := range namespaces {
+			list, err := client.RuleList(types.ListOptions{Namespace: namespace})
+			if err != nil {
+				return nil, err
+			}
+			for _, rule := range list {
+				objects = append(objects, withID(FromRule(rule), rule.ID))
+			}
+		}
+	case UserKind:
+		list, err := client.UserList(types.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range list {
+			objects = append(objects, withID(FromUser(user), user.UUID))
+		}
+	case PolicyKind:
+		set, err := client.PolicyList(types.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range set.GetPoliciesWithID() {
+			objects = append(objects, withID(FromPolicy(p.Policy), p.ID))
+		}
+	case VolumeKind:
+		for _, namespace := range namespaces {
+			list, err := client.VolumeList(types.ListOptions{Namespace: namespace})
+			if err != nil {
+				return nil, err
+			}
+			for _, vol := range list {
+				objects = append(objects, withID(FromVolume(vol), vol.ID))
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown kind %q", kind)
+	}
+	return objects, nil
+}
+
+func withID(o *Object, id string) *Object {
+	o.ID = id
+	return o
+}
+
+// Namespaces returns the namespaces of the given objects, in the order
+// they first appear.
+func Namespaces(objects []*Object) []string {
+	var namespaces []string
+	seen := make(map[string]bool)
+	for _, o := range objects {
+		if ns := o.Metadata.Namespace; ns != "" && !seen[ns] {
+			seen[ns] = true
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
+// Get returns the live object described by o, or nil if there is none.
+// Policies, which can't be got by their spec, are looked up in the list of
+// policies.
+func Get(client *api.Client, o *Object) (*Object, error) {
+	var live *Object
+	var err error
+	switch o.Kind {
+	case NamespaceKind:
+		var ns *types.Namespace
+		if ns, err = client.Namespace(o.Metadata.Name); err == nil {
+			live = withID(FromNamespace(ns), ns.ID)
+		}
+	case PoolKind:
+		var pool *types.Pool
+		if pool, err = client.Pool(o.Metadata.Name); err == nil {
+			live = withID(FromPool(pool), pool.ID)
+		}
+	case TemplateKind:
+		var tmpl *types.Template
+		if tmpl, err = client.Template(o.Metadata.Name); err == nil {
+			live = withID(FromTemplate(tmpl), tmpl.ID)
+		}
+	case RuleKind:
+		var rule *types.Rule
+		if rule, err = client.Rule(o.Metadata.Namespace, o.Metadata.Name); err == nil {
+			live = withID(FromRule(rule), rule.ID)
+		}
+	case UserKind:
+		var user *types.User
+		if user, err = client.User(o.Metadata.Name); err == nil {
+			live = withID(FromUser(user), user.UUID)
+		}
+	case VolumeKind:
+		var vol *types.Volume
+		if vol, err = client.Volume(o.Metadata.Namespace, o.Metadata.Name); err == nil {
+			live = withID(FromVolume(vol), vol.ID)
+		}
+	case PolicyKind:
+		policies, err := List(client, PolicyKind, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range policies {
+			if p.String() == o.String() {
+				return p, nil
+			}
+		}
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown kind %q", o.Kind)
+	}
+
+	switch err {
+	case nil:
+		return live, nil
+	case api.ErrNoSuchNamespace, api.ErrNoSuchPool, api.ErrNoSuchTemplate, api.ErrNoSuchRule, api.ErrNoSuchUser, api.ErrNoSuchVolume:
+		return nil, nil
+	}
+	return nil, err
+}
@@ -0,0 +1,514 @@
+// Package manifest reads and writes declarative descriptions of StorageOS
+// objects.
+//
+// A manifest is a YAML or JSON file holding one or more documents, each
+// describing an object with a kind/metadata/spec layout:
+//
+//	apiVersion: v1
+//	kind: Volume
+//	metadata:
+//	  name: db
+//	  namespace: prod
+//	  labels:
+//	    app: postgres
+//	spec:
+//	  size: 20
+//
+// The kinds are Namespace, Pool, Template, Rule, User, Policy and Volume.
+// Policies have no name, and are identified by their spec.
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/storageos/go-cli/pkg/selector"
+	"github.com/storageos/go-cli/pkg/validation"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// APIVersion is the version of the manifest format.
+const APIVersion = "v1"
+
+// Kinds of objects, in the order they are applied.
+const (
+	NamespaceKind = "Namespace"
+	PoolKind      = "Pool"
+	TemplateKind  = "Template"
+	RuleKind      = "Rule"
+	UserKind      = "User"
+	PolicyKind    = "Policy"
+	VolumeKind    = "Volume"
+)
+
+// Kinds lists the kinds of objects, in the order they are applied. Objects
+// are removed in the reverse order.
+var Kinds = []string{NamespaceKind, PoolKind, TemplateKind, RuleKind, UserKind, PolicyKind, VolumeKind}
+
+// Metadata identifies an object.
+type Metadata struct {
+	Name      string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Namespace string            `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+// Object is an object described by a manifest. Spec is a *NamespaceSpec,
+// *PoolSpec, *TemplateSpec, *RuleSpec, *UserSpec, *PolicySpec or
+// *VolumeSpec, depending on the kind.
+type Object struct {
+	APIVersion string      `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string      `json:"kind" yaml:"kind"`
+	Metadata   Metadata    `json:"metadata" yaml:"metadata"`
+	Spec       interface{} `json:"spec" yaml:"spec"`
+
+	// Source is the file and line the object was read from.
+	Source string `json:"-" yaml:"-"`
+
+	// ID is the ID of a live object.
+	ID string `json:"-" yaml:"-"`
+}
+
+// NamespaceSpec is the spec of a namespace.
+type NamespaceSpec struct {
+	DisplayName string `json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// PoolSpec is the spec of a capacity pool.
+type PoolSpec struct {
+	Description     string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Default         bool     `json:"default,omitempty" yaml:"default,omitempty"`
+	DefaultDriver   string   `json:"defaultDriver,omitempty" yaml:"defaultDriver,omitempty"`
+	ControllerNames []string `json:"controllerNames,omitempty" yaml:"controllerNames,omitempty"`
+	DriverNames     []string `json:"driverNames,omitempty" yaml:"driverNames,omitempty"`
+	Active          bool     `json:"active" yaml:"active"`
+}
+
+// TemplateSpec is the spec of a name template. Labels are the labels an
+// object must have for the template to apply to it.
+type TemplateSpec struct {
+	Description   string            `json:"description,omitempty" yaml:"description,omitempty"`
+	Format        string            `json:"format,omitempty" yaml:"format,omitempty"`
+	AutoIncrement bool              `json:"autoIncrement,omitempty" yaml:"autoIncrement,omitempty"`
+	Padding       bool              `json:"padding,omitempty" yaml:"padding,omitempty"`
+	PaddingLength int               `json:"paddingLength" yaml:"paddingLength"`
+	Active        bool              `json:"active" yaml:"active"`
+	Weight        int               `json:"weight" yaml:"weight"`
+	ObjectTypes   []string          `json:"objectTypes,omitempty" yaml:"objectTypes,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+// RuleSpec is the spec of a rule. Labels are the labels the rule adds to or
+// removes from the volumes it selects.
+type RuleSpec struct {
+	Description string            `json:"description,omitempty" yaml:"description,omitempty"`
+	Active      bool              `json:"active" yaml:"active"`
+	Weight      int               `json:"weight" yaml:"weight"`
+	Action      string            `json:"action" yaml:"action"`
+	Selector    string            `json:"selector,omitempty" yaml:"selector,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+// UserSpec is the spec of a user. Password is only used to create the user,
+// and is never read back from the server.
+type UserSpec struct {
+	Groups   []string `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Role     string   `json:"role" yaml:"role"`
+	Password string   `json:"password,omitempty" yaml:"password,omitempty"`
+}
+
+// PolicySpec is the spec of a policy, as in types.Policy.
+type PolicySpec struct {
+	User            string `json:"user,omitempty" yaml:"user,omitempty"`
+	Group           string `json:"group,omitempty" yaml:"group,omitempty"`
+	Readonly        bool   `json:"readonly,omitempty" yaml:"readonly,omitempty"`
+	APIGroup        string `json:"apiGroup,omitempty" yaml:"apiGroup,omitempty"`
+	Resource        string `json:"resource,omitempty" yaml:"resource,omitempty"`
+	Namespace       string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	NonResourcePath string `json:"nonResourcePath,omitempty" yaml:"nonResourcePath,omitempty"`
+}
+
+// VolumeSpec is the spec of a volume. Pool and FSType are chosen by the
+// server when left empty, and can't be changed once the volume exists.
+type VolumeSpec struct {
+	Description  string `json:"description,omitempty" yaml:"description,omitempty"`
+	Size         int    `json:"size" yaml:"size"`
+	Pool         string `json:"pool,omitempty" yaml:"pool,omitempty"`
+	FSType       string `json:"fsType,omitempty" yaml:"fsType,omitempty"`
+	NodeSelector string `json:"nodeSelector,omitempty" yaml:"nodeSelector,omitempty"`
+}
+
+// newSpec returns the spec for kind, with the same defaults as the create
+// commands.
+func newSpec(kind string) interface{} {
+	switch kind {
+	case NamespaceKind:
+		return &NamespaceSpec{}
+	case PoolKind:
+		return &PoolSpec{Active: true}
+	case TemplateKind:
+		return &TemplateSpec{PaddingLength: 3, Active: true, Weight: 5}
+	case RuleKind:
+		return &RuleSpec{Active: true, Weight: 5, Action: "add"}
+	case UserKind:
+		return &UserSpec{Role: "user"}
+	case PolicyKind:
+		return &PolicySpec{}
+	case VolumeKind:
+		return &VolumeSpec{Size: 5}
+	}
+	return nil
+}
+
+// Namespaced returns true for the kinds of objects that belong to a
+// namespace.
+func Namespaced(kind string) bool {
+	return kind == RuleKind || kind == VolumeKind
+}
+
+// Ref returns the reference of the object: namespace/name for namespaced
+// objects, the name for others, and the set fields of the spec for
+// policies, which have no name.
+func (o *Object) Ref() string {
+	if p, ok := o.Spec.(*PolicySpec); ok {
+		return p.String()
+	}
+	if Namespaced(o.Kind) {
+		return o.Metadata.Namespace + "/" + o.Metadata.Name
+	}
+	return o.Metadata.Name
+}
+
+// String returns the kind and reference of the object, such as
+// volume/default/db.
+func (o *Object) String() string {
+	return strings.ToLower(o.Kind) + "/" + o.Ref()
+}
+
+// String returns the set fields of the policy, such as
+// user=alice,namespace=prod.
+func (p *PolicySpec) String() string {
+	var fields []string
+	add := func(name, value string) {
+		if value != "" {
+			fields = append(fields, name+"="+value)
+		}
+	}
+	add("user", p.User)
+	add("group", p.Group)
+	if p.Readonly {
+		add("readonly", "true")
+	}
+	add("apiGroup", p.APIGroup)
+	add("resource", p.Resource)
+	add("namespace", p.Namespace)
+	add("nonResourcePath", p.NonResourcePath)
+	return strings.Join(fields, ",")
+}
+
+// document is an object as read, before its spec is decoded for its kind.
+type document struct {
+	APIVersion string   `yaml:"apiVersion"`
+	Kind       string   `yaml:"kind"`
+	Metadata   Metadata `yaml:"metadata"`
+	Spec       rawSpec  `yaml:"spec"`
+}
+
+// rawSpec defers decoding the spec until the kind is known.
+type rawSpec struct {
+	unmarshal func(interface{}) error
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (r *rawSpec) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	r.unmarshal = unmarshal
+	return nil
+}
+
+var (
+	yamlLineRE = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+	yamlTypeRE = regexp.MustCompile(` in type \S+$`)
+)
+
+// YAMLError reports the errors of the YAML decoder with the file and line
+// they are found at, given that the document starts at line.
+func YAMLError(err error, source string, line int) error {
+	msgs := []string{err.Error()}
+	if e, ok := err.(*yaml.TypeError); ok {
+		msgs = e.Errors
+	}
+	for i, msg := range msgs {
+		msg = yamlTypeRE.ReplaceAllString(strings.TrimPrefix(msg, "yaml: "), "")
+		if m := yamlLineRE.FindStringSubmatch(msg); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			msgs[i] = fmt.Sprintf("%s:%d: %s", source, n+line-1, m[2])
+			continue
+		}
+		msgs[i] = fmt.Sprintf("%s:%d: %s", source, line, msg)
+	}
+	return errors.New(strings.Join(msgs, "\n"))
+}
+
+// Decode reads the objects in data, a YAML or JSON manifest which may hold
+// several documents separated by "---" lines. Source names data in errors.
+// Namespaced objects without a namespace are put in namespace.
+func Decode(data []byte, source string, namespace string) ([]*Object, error) {
+	var objects []*Object
+	for _, doc := range SplitDocuments(data) {
+		o, err := decodeDocument(doc.Data)
+		if err != nil {
+			return nil, YAMLError(err, source, doc.Line)
+		}
+		if o == nil {
+			continue
+		}
+		o.Source = fmt.Sprintf("%s:%d", source, doc.Line)
+		if o.Spec == nil {
+			return nil, fmt.Errorf("%s: unknown kind %q, expected one of %s", o.Source, o.Kind, strings.Join(Kinds, ", "))
+		}
+		if err := o.validate(namespace); err != nil {
+			return nil, fmt.Errorf("%s: %s %v", o.Source, strings.ToLower(o.Kind), err)
+		}
+		objects = append(objects, o)
+	}
+	return objects, nil
+}
+
+// Document is one of the documents of a YAML stream, starting at Line.
+type Document struct {
+	Data []byte
+	Line int
+}
+
+// SplitDocuments splits a YAML stream on "---" lines, keeping the line
+// each document starts on.
+func SplitDocuments(data []byte) []Document {
+	var docs []Document
+	cur := Document{Line: 1}
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	for i, line := range lines {
+		trimmed := bytes.TrimRight(line, " \t\r\n")
+		if bytes.Equal(trimmed, []byte("---")) {
+			docs = append(docs, cur)
+			cur = Document{Line: i + 2}
+			continue
+		}
+		cur.Data = append(cur.Data, line...)
+	}
+	return append(docs, cur)
+}
+
+func decodeDocument(data []byte) (*Object, error) {
+	var doc document
+	if err := yaml.UnmarshalStrict(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.APIVersion == "" && doc.Kind == "" && doc.Spec.unmarshal == nil && doc.Metadata.Name == "" {
+		// Empty document, or only comments.
+		return nil, nil
+	}
+
+	o := &Object{
+		APIVersion: doc.APIVersion,
+		Kind:       doc.Kind,
+		Metadata:   doc.Metadata,
+		Spec:       newSpec(doc.Kind),
+	}
+	if o.Spec == nil {
+		return o, nil
+	}
+	if doc.Spec.unmarshal != nil {
+		if err := doc.Spec.unmarshal(o.Spec); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+func (o *Object) validate(namespace string) error {
+	switch {
+	case o.APIVersion != APIVersion:
+		return fmt.Errorf("has apiVersion %q, expected %q", o.APIVersion, APIVersion)
+	case o.Kind == PolicyKind:
+		if o.Metadata.Name != "" || o.Metadata.Namespace != "" || len(o.Metadata.Labels) > 0 {
+			return fmt.Errorf("metadata can't be set, policies are identified by their spec")
+		}
+	case o.Metadata.Name == "":
+		return fmt.Errorf("has no metadata.name")
+	case Namespaced(o.Kind):
+		if o.Metadata.Namespace == "" {
+			o.Metadata.Namespace = namespace
+		}
+	case o.Metadata.Namespace != "":
+		return fmt.Errorf("%s can't have a metadata.namespace", o.Metadata.Name)
+	}
+
+	switch spec := o.Spec.(type) {
+	case *PoolSpec:
+		sort.Strings(spec.ControllerNames)
+		sort.Strings(spec.DriverNames)
+	case *TemplateSpec:
+		if len(o.Metadata.Labels) > 0 {
+			return fmt.Errorf("%s can't have metadata.labels, the labels it matches are spec.labels", o.Ref())
+		}
+		if spec.Weight < 0 || spec.Weight > 10 {
+			return fmt.Errorf("%s has weight %d, expected 0 to 10", o.Ref(), spec.Weight)
+		}
+	case *RuleSpec:
+		if len(o.Metadata.Labels) > 0 {
+			return fmt.Errorf("%s can't have metadata.labels, the labels it sets are spec.labels", o.Ref())
+		}
+		if spec.Action != "add" && spec.Action != "remove" {
+			return fmt.Errorf("%s has invalid action %q, expected add or remove", o.Ref(), spec.Action)
+		}
+		if _, err := selector.Parse(spec.Selector); err != nil {
+			return fmt.Errorf("%s has %v", o.Ref(), err)
+		}
+		if spec.Weight < 0 || spec.Weight > 10 {
+			return fmt.Errorf("%s has weight %d, expected 0 to 10", o.Ref(), spec.Weight)
+		}
+	case *UserSpec:
+		if spec.Role != "user" && spec.Role != "admin" {
+			return fmt.Errorf("%s has invalid role %q, expected user or admin", o.Ref(), spec.Role)
+		}
+		if spec.Password != "" && len(spec.Password) < 8 {
+			return fmt.Errorf("%s has a password shorter than 8 characters", o.Ref())
+		}
+		sort.Strings(spec.Groups)
+	case *PolicySpec:
+		if spec.User == "" && spec.Group == "" {
+			return fmt.Errorf("spec needs a user or a group")
+		}
+	case *VolumeSpec:
+		if spec.Size <= 0 {
+			return fmt.Errorf("%s has size %d, expected a positive size in GB", o.Ref(), spec.Size)
+		}
+		if spec.FSType != "" {
+			if err := validation.IsValidFSType(spec.FSType); err != nil {
+				return fmt.Errorf("%s: %v", o.Ref(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// ReadFiles reads the objects in the manifests at paths, reading stdin for
+// "-" and the .yaml, .yml and .json files of directories. An object may
+// only be described once. Namespaced objects without a namespace are put in
+// namespace.
+func ReadFiles(paths []string, stdin io.Reader, namespace string) ([]*Object, error) {
+	var objects []*Object
+	for _, path := range paths {
+		files := []string{path}
+		if path != "-" {
+			fi, err := os.Stat(path)
+			if err != nil {
+				return nil, err
+			}
+			if fi.IsDir() {
+				if files, err = manifestFiles(path); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		for _, file := range files {
+			var data []byte
+			var err error
+			if file == "-" {
+				data, err = ioutil.ReadAll(stdin)
+				file = "<stdin>"
+			} else {
+				data, err = ioutil.ReadFile(file)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			objs, err := Decode(data, file, namespace)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, objs...)
+		}
+	}
+
+	seen := make(map[string]*Object)
+	for _, o := range objects {
+		if prev, ok := seen[o.String()]; ok {
+			return nil, fmt.Errorf("%s: %s is already described at %s", o.Source, o, prev.Source)
+		}
+		seen[o.String()] = o
+	}
+	return objects, nil
+}
+
+func manifestFiles(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		switch filepath.Ext(e.Name()) {
+		case ".yaml", ".yml", ".json":
+			if !e.IsDir() {
+				files = append(files, filepath.Join(dir, e.Name()))
+			}
+		}
+	}
+	return files, nil
+}
+
+// Encode writes objects as a YAML manifest.
+func Encode(w io.Writer, objects []*Object) error {
+	for i, o := range objects {
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+		data, err := yaml.Marshal(o)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ByKind returns the objects of the given kind.
+func ByKind(objects []*Object, kind string) []*Object {
+	var matched []*Object
+	for _, o := range objects {
+		if o.Kind == kind {
+			matched = append(matched, o)
+		}
+	}
+	return matched
+}
+
+// Equal returns true if a and b describe the same object in the same state.
+func Equal(a, b *Object) bool {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(ja, jb)
+}
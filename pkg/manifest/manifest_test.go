@@ -0,0 +1,138 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/storageos/go-api/types"
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+const testManifest = `# Production
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: prod
+spec:
+  description: Production
+---
+apiVersion: v1
+kind: Volume
+metadata:
+  name: db
+  namespace: prod
+  labels:
+    app: postgres
+spec:
+  size: 20
+---
+apiVersion: v1
+kind: Rule
+metadata:
+  name: replicated
+spec:
+  selector: env==prod
+  labels:
+    storageos.feature.replicas: "2"
+---
+---
+apiVersion: v1
+kind: Policy
+spec:
+  group: devs
+  namespace: prod
+`
+
+func TestDecode(t *testing.T) {
+	objects, err := Decode([]byte(testManifest), "test.yaml", "default")
+	assert.NilError(t, err)
+	assert.Equal(t, len(objects), 4)
+
+	assert.Equal(t, objects[0].String(), "namespace/prod")
+	assert.Equal(t, objects[0].Source, "test.yaml:1")
+	assert.Equal(t, objects[0].Spec.(*NamespaceSpec).Description, "Production")
+
+	assert.Equal(t, objects[1].String(), "volume/prod/db")
+	assert.Equal(t, objects[1].Source, "test.yaml:9")
+	assert.Equal(t, objects[1].Spec.(*VolumeSpec).Size, 20)
+	assert.Equal(t, objects[1].Metadata.Labels["app"], "postgres")
+
+	// Defaults are those of the create commands.
+	rule := objects[2].Spec.(*RuleSpec)
+	assert.Equal(t, objects[2].String(), "rule/default/replicated")
+	assert.Equal(t, rule.Active, true)
+	assert.Equal(t, rule.Weight, 5)
+	assert.Equal(t, rule.Action, "add")
+
+	assert.Equal(t, objects[3].String(), "policy/group=devs,namespace=prod")
+}
+
+func TestDecodeJSON(t *testing.T) {
+	objects, err := Decode([]byte(`{"apiVersion": "v1", "kind": "Pool", "metadata": {"name": "ssd"}, "spec": {"driverNames": ["filesystem"]}}`), "pool.json", "default")
+	assert.NilError(t, err)
+	assert.Equal(t, len(objects), 1)
+	assert.Equal(t, objects[0].Spec.(*PoolSpec).Active, true)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, tt := range []struct {
+		manifest string
+		err      string
+	}{
+		{
+			manifest: "apiVersion: v1\nkind: Volume\nmetadata:\n  name: db\n---\napiVersion: v1\nkind: Volume\nmetadata:\n  name: db\nspec:\n  sise: 5\n",
+			err:      "test.yaml:11: field sise not found",
+		},
+		{
+			manifest: "apiVersion: v1\nkind: Volume\nmetadata:\n  name: db\nspec:\n  size: big\n",
+			err:      "test.yaml:6: cannot unmarshal !!str `big` into int",
+		},
+		{manifest: "apiVersion: v1\nkind: Node\n", err: `test.yaml:1: unknown kind "Node"`},
+		{manifest: "apiVersion: v2\nkind: Pool\n", err: `pool has apiVersion "v2", expected "v1"`},
+		{manifest: "apiVersion: v1\nkind: Pool\n", err: "pool has no metadata.name"},
+		{manifest: "apiVersion: v1\nkind: Pool\nmetadata:\n  name: a\n  namespace: b\n", err: "pool a can't have a metadata.namespace"},
+		{manifest: "apiVersion: v1\nkind: Volume\nmetadata:\n  name: db\nspec:\n  size: 0\n", err: "volume default/db has size 0"},
+		{manifest: "apiVersion: v1\nkind: Rule\nmetadata:\n  name: r\nspec:\n  action: set\n", err: `rule default/r has invalid action "set"`},
+		{manifest: "apiVersion: v1\nkind: Rule\nmetadata:\n  name: r\nspec:\n  selector: env in prod\n", err: `rule default/r has invalid selector "env in prod": expected (, found "prod" at position 8`},
+		{manifest: "apiVersion: v1\nkind: Policy\nmetadata:\n  name: p\nspec:\n  user: a\n", err: "policy metadata can't be set"},
+		{manifest: "apiVersion: v1\nkind: Policy\nspec:\n  namespace: a\n", err: "policy spec needs a user or a group"},
+		{manifest: "apiVersion: v1\nkind: User\nmetadata:\n  name: a\nspec:\n  role: root\n", err: `user a has invalid role "root"`},
+		{manifest: "apiVersion: v1\nkind: Template\nmetadata:\n  name: t\n  labels:\n    app: db\n", err: "template t can't have metadata.labels"},
+	} {
+		_, err := Decode([]byte(tt.manifest), "test.yaml", "default")
+		assert.Error(t, err, tt.err)
+	}
+}
+
+func TestFromUser(t *testing.T) {
+	o := FromUser(&types.User{UUID: "1", Username: "alice", Groups: []string{""}, Role: "user", Password: "secret"})
+	assert.Equal(t, o.String(), "user/alice")
+	assert.DeepEqual(t, o.Spec, &UserSpec{Role: "user"})
+
+	// The password of a desired user is never a change.
+	objects, err := Decode([]byte("apiVersion: v1\nkind: User\nmetadata:\n  name: alice\nspec:\n  password: verysecret\n"), "test.yaml", "default")
+	assert.NilError(t, err)
+	assert.Equal(t, Equal(Complete(objects[0], o), o), true)
+}
+
+func TestComplete(t *testing.T) {
+	live := FromVolume(&types.Volume{
+		Name:      "db",
+		Namespace: "prod",
+		Size:      20,
+		Pool:      "default",
+		FSType:    "ext4",
+		Labels:    map[string]string{"app": "postgres", "storageos.feature.replicas": "1"},
+	})
+
+	objects, err := Decode([]byte("apiVersion: v1\nkind: Volume\nmetadata:\n  name: db\n  namespace: prod\n  labels:\n    app: postgres\nspec:\n  size: 20\n"), "test.yaml", "default")
+	assert.NilError(t, err)
+	desired := objects[0]
+
+	// Fields left to the server, and labels set by rules, aren't changes.
+	assert.Equal(t, Equal(desired, live), false)
+	assert.Equal(t, Equal(Complete(desired, live), live), true)
+	assert.Equal(t, desired.Spec.(*VolumeSpec).Pool, "")
+
+	desired.Spec.(*VolumeSpec).Size = 30
+	assert.Equal(t, Equal(Complete(desired, live), live), false)
+}
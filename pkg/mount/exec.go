@@ -14,12 +14,14 @@ import (
 const (
 	mount     = "/bin/mount"
 	umount    = "/bin/umount"
-	file      = "/usr/bin/file"
 	mkfsExt2  = "/sbin/mkfs.ext2"
 	mkfsExt3  = "/sbin/mkfs.ext3"
 	mkfsExt4  = "/sbin/mkfs.ext4"
 	mkfsXfs   = "/sbin/mkfs.xfs"
 	mkfsBtrfs = "/bin/mkfs.btrfs"
+	resize2fs = "/sbin/resize2fs"
+	xfsGrowfs = "/sbin/xfs_growfs"
+	btrfs     = "/bin/btrfs"
 )
 
 func runMount(ctx context.Context, args ...string) (string, error) {
@@ -30,10 +32,6 @@ func runUmount(ctx context.Context, args ...string) (string, error) {
 	return runCmd(ctx, umount, args...)
 }
 
-func runFile(ctx context.Context, args ...string) (string, error) {
-	return runCmd(ctx, file, args...)
-}
-
 func runCmd(ctx context.Context, cmd string, args ...string) (string, error) {
 	command := exec.CommandContext(ctx, cmd, args...)
 	out, err := command.Output()
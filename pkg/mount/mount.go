@@ -3,14 +3,42 @@ package mount
 import (
 	"context"
 	"os"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 )
 
 // Driver - generic mount driver interface
 type Driver interface {
-	MountVolume(ctx context.Context, volumeID, fsType, mountpoint string, mkfs bool) error
+	MountVolume(ctx context.Context, volumeID, mountpoint, fsType string, opts MountOptions, mkfs bool) error
 	UnmountVolume(ctx context.Context, mountpoint string) error
+	ResizeVolume(ctx context.Context, volumeID, mountpoint string, size uint64) (uint64, error)
+}
+
+// MountOptions - options a volume is mounted with
+type MountOptions struct {
+	// ReadOnly mounts the filesystem read-only.
+	ReadOnly bool
+
+	// Options are passed to mount as-is, e.g. noatime or discard.
+	Options []string
+
+	// MkfsOptions are the arguments passed to mkfs when the volume is
+	// formatted, e.g. ["-i", "8192"] or ["-m", "reflink=1"]. They are checked
+	// with validation.IsValidMkfsArgs first.
+	MkfsOptions []string
+}
+
+// args returns the mount command arguments for the options.
+func (o MountOptions) args() []string {
+	options := o.Options
+	if o.ReadOnly {
+		options = append([]string{"ro"}, options...)
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	return []string{"-o", strings.Join(options, ",")}
 }
 
 // DefaultDriver - default mount driver
@@ -26,8 +54,8 @@ func New(deviceRootPath string) *DefaultDriver {
 }
 
 // MountVolume - mounts specified volume
-func (d *DefaultDriver) MountVolume(ctx context.Context, id, mountpoint, fsType string, mkfs bool) error {
-	return mountVolume(ctx, d.deviceRootPath, id, mountpoint, fsType, mkfs)
+func (d *DefaultDriver) MountVolume(ctx context.Context, id, mountpoint, fsType string, opts MountOptions, mkfs bool) error {
+	return mountVolume(ctx, d.deviceRootPath, id, mountpoint, fsType, opts, mkfs)
 }
 
 // UnmountVolume - unmounts specified mountpoint
@@ -35,6 +63,12 @@ func (d *DefaultDriver) UnmountVolume(ctx context.Context, mountpoint string) er
 	return unmountVolume(ctx, mountpoint)
 }
 
+// ResizeVolume - grows the filesystem of a mounted volume to the new size of
+// its device, returning the usable size of the filesystem in bytes
+func (d *DefaultDriver) ResizeVolume(ctx context.Context, id, mountpoint string, size uint64) (uint64, error) {
+	return resizeVolume(ctx, d.deviceRootPath+"/"+id, mountpoint, size)
+}
+
 // deviceRootPath is the location of the StorageOS raw volumes.
 // const deviceRootPath = constants.DeviceRootPath
 
@@ -47,15 +81,16 @@ const mountpointPerms os.FileMode = 0700
 // It checks the volume first, waiting 30 seconds for it to be created, and
 // creates an ext4 filesystem on it if there isn't already a filesystem.  The
 // mount will fail if the mount command can't determine the fstype.
-func mountVolume(ctx context.Context, deviceRootPath string, id string, mp string, fsType string, shouldMkfs bool) error {
+func mountVolume(ctx context.Context, deviceRootPath string, id string, mp string, fsType string, opts MountOptions, shouldMkfs bool) error {
 
 	// first time mount
 	if shouldMkfs {
-		if err := initRawVolume(ctx, deviceRootPath+"/"+id, fsType); err != nil {
+		if err := initRawVolume(ctx, deviceRootPath+"/"+id, fsType, opts.MkfsOptions); err != nil {
 			log.WithFields(log.Fields{
-				"id":      id,
-				"fs_type": fsType,
-				"err":     err.Error(),
+				"id":           id,
+				"fs_type":      fsType,
+				"mkfs_options": opts.MkfsOptions,
+				"err":          err.Error(),
 			}).Error("volume init error")
 			return err
 		}
@@ -67,12 +102,14 @@ func mountVolume(ctx context.Context, deviceRootPath string, id string, mp strin
 	}
 	log.Debugf("Mountpoint created: %s ", mp)
 
-	_, err := runMount(ctx, deviceRootPath+"/"+id, mp)
+	args := append(opts.args(), deviceRootPath+"/"+id, mp)
+	_, err := runMount(ctx, args...)
 	if err != nil {
 		log.WithFields(log.Fields{
 			"path":        deviceRootPath + "/" + id,
 			"mount_point": mp,
 			"fs_type":     fsType,
+			"options":     opts.args(),
 			"error":       err,
 		}).Error("Mount failed")
 		return err
@@ -0,0 +1,14 @@
+package mount
+
+import (
+	"testing"
+
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+func TestMountOptionsArgs(t *testing.T) {
+	assert.DeepEqual(t, MountOptions{}.args(), []string(nil))
+	assert.DeepEqual(t, MountOptions{ReadOnly: true}.args(), []string{"-o", "ro"})
+	assert.DeepEqual(t, MountOptions{Options: []string{"noatime", "discard"}}.args(), []string{"-o", "noatime,discard"})
+	assert.DeepEqual(t, MountOptions{ReadOnly: true, Options: []string{"noatime"}}.args(), []string{"-o", "ro,noatime"})
+}
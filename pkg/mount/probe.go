@@ -0,0 +1,147 @@
+package mount
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+)
+
+// probeSize is the number of bytes read from the start of a volume when
+// looking for a filesystem. It must cover the btrfs superblock, which lives
+// furthest in at 64KiB.
+const probeSize = btrfsMagicOffset + 4096
+
+// rawFSType is reported for volumes that contain no data and can safely be
+// formatted.
+const rawFSType = "raw"
+
+// Superblock locations and magic numbers.
+const (
+	extSuperblockOffset = 1024
+	extMagicOffset      = extSuperblockOffset + 0x38
+	extCompatOffset     = extSuperblockOffset + 0x5c
+	extIncompatOffset   = extSuperblockOffset + 0x60
+	extROCompatOffset   = extSuperblockOffset + 0x64
+	extMagic            = 0xef53
+
+	btrfsMagicOffset = 0x10040
+
+	fatOEMOffset    = 3
+	fat16TypeOffset = 54
+	fat32TypeOffset = 82
+
+	mbrSignatureOffset     = 510
+	mbrPartitionOffset     = 446
+	mbrPartitionEntrySize  = 16
+	mbrPartitionTypeOffset = 4
+	gptHeaderOffset        = 512
+)
+
+// ext feature flags. Anything ext3 doesn't know about makes it ext4.
+const (
+	extCompatHasJournal   = 0x0004
+	extIncompatJournalDev = 0x0008
+	ext3IncompatSupported = 0x0002 | 0x0004 | 0x0010
+	ext3ROCompatSupported = 0x0001 | 0x0002 | 0x0004
+)
+
+var (
+	xfsMagic     = []byte("XFSB")
+	btrfsMagic   = []byte("_BHRfS_M")
+	ntfsMagic    = []byte("NTFS    ")
+	gptMagic     = []byte("EFI PART")
+	mbrSignature = []byte{0x55, 0xaa}
+)
+
+// probeFSType reads the start of the volume at path and returns its
+// filesystem type, or "raw" if the volume holds no data at all. An error is
+// returned for partition tables and any data that isn't recognised, as the
+// volume must not be formatted in either case.
+func probeFSType(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return detectFSType(f)
+}
+
+// detectFSType identifies the filesystem from the superblocks readable
+// through r. Reads past the end of r are treated as zeroes, so small images
+// can be probed too.
+func detectFSType(r io.ReaderAt) (string, error) {
+	buf := make([]byte, probeSize)
+	n, err := r.ReadAt(buf, 0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	buf = buf[:n]
+
+	magicAt := func(offset int, magic []byte) bool {
+		return len(buf) >= offset+len(magic) && bytes.Equal(buf[offset:offset+len(magic)], magic)
+	}
+
+	switch {
+	case len(buf) >= extROCompatOffset+4 && binary.LittleEndian.Uint16(buf[extMagicOffset:]) == extMagic:
+		return extFSType(buf)
+	case magicAt(0, xfsMagic):
+		return "xfs", nil
+	case magicAt(btrfsMagicOffset, btrfsMagic):
+		return "btrfs", nil
+	case magicAt(fatOEMOffset, ntfsMagic):
+		return "ntfs", nil
+	case magicAt(mbrSignatureOffset, mbrSignature) && isFAT(buf):
+		return "fat", nil
+	case magicAt(gptHeaderOffset, gptMagic):
+		return "", fmt.Errorf("detected GPT partition table, aborting")
+	case magicAt(mbrSignatureOffset, mbrSignature) && hasMBRPartitions(buf):
+		return "", fmt.Errorf("detected MBR partition table, aborting")
+	}
+
+	for _, b := range buf {
+		if b != 0 {
+			return "", fmt.Errorf("unknown data found on volume, refusing to create a filesystem")
+		}
+	}
+	return rawFSType, nil
+}
+
+// extFSType tells ext2, ext3 and ext4 apart by the features they use.
+func extFSType(buf []byte) (string, error) {
+	compat := binary.LittleEndian.Uint32(buf[extCompatOffset:])
+	incompat := binary.LittleEndian.Uint32(buf[extIncompatOffset:])
+	roCompat := binary.LittleEndian.Uint32(buf[extROCompatOffset:])
+
+	switch {
+	case incompat&extIncompatJournalDev != 0:
+		return "", fmt.Errorf("detected ext external journal device, aborting")
+	case incompat&^ext3IncompatSupported != 0, roCompat&^ext3ROCompatSupported != 0:
+		return "ext4", nil
+	case compat&extCompatHasJournal != 0:
+		return "ext3", nil
+	}
+	return "ext2", nil
+}
+
+// isFAT returns true if the boot sector names a FAT filesystem, either in the
+// FAT12/16 or the FAT32 extended boot record.
+func isFAT(buf []byte) bool {
+	return bytes.HasPrefix(buf[fat16TypeOffset:], []byte("FAT1")) ||
+		bytes.HasPrefix(buf[fat32TypeOffset:], []byte("FAT32")) ||
+		bytes.HasPrefix(buf[fatOEMOffset:], []byte("mkfs.fat")) ||
+		bytes.HasPrefix(buf[fatOEMOffset:], []byte("MSDOS"))
+}
+
+// hasMBRPartitions returns true if any of the four primary partition entries
+// is in use.
+func hasMBRPartitions(buf []byte) bool {
+	for i := 0; i < 4; i++ {
+		if buf[mbrPartitionOffset+i*mbrPartitionEntrySize+mbrPartitionTypeOffset] != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package mount
+
+import (
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+// image describes a volume image as a set of byte patches over zeroes.
+type image map[int][]byte
+
+func le16(v uint16) []byte {
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, v)
+	return b
+}
+
+func le32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, v)
+	return b
+}
+
+func extImage(compat, incompat, roCompat uint32) image {
+	return image{
+		extMagicOffset:    le16(extMagic),
+		extCompatOffset:   le32(compat),
+		extIncompatOffset: le32(incompat),
+		extROCompatOffset: le32(roCompat),
+	}
+}
+
+func writeImage(t *testing.T, dir, name string, size int, img image) string {
+	buf := make([]byte, size)
+	for offset, data := range img {
+		copy(buf[offset:], data)
+	}
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, buf, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestProbeFSType(t *testing.T) {
+	dir, err := ioutil.TempDir("", "probe")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name     string
+		size     int
+		img      image
+		expected string
+		err      string
+	}{
+		{name: "raw", size: 1 << 20, expected: rawFSType},
+		{name: "empty", size: 0, expected: rawFSType},
+		{name: "small", size: 512, expected: rawFSType},
+		{name: "ext2", size: probeSize, img: extImage(0x0038, 0x0002, 0x0003), expected: "ext2"},
+		{name: "ext3", size: probeSize, img: extImage(0x003c, 0x0002, 0x0003), expected: "ext3"},
+		{name: "ext4-extents", size: probeSize, img: extImage(0x003c, 0x02c2, 0x0003), expected: "ext4"},
+		{name: "ext4-huge-file", size: probeSize, img: extImage(0x003c, 0x0002, 0x006b), expected: "ext4"},
+		{name: "ext-journal-dev", size: probeSize, img: extImage(0, extIncompatJournalDev, 0), err: "external journal"},
+		{name: "xfs", size: probeSize, img: image{0: xfsMagic}, expected: "xfs"},
+		{name: "btrfs", size: probeSize + 4096, img: image{btrfsMagicOffset: btrfsMagic}, expected: "btrfs"},
+		{name: "ntfs", size: probeSize, img: image{fatOEMOffset: ntfsMagic, mbrSignatureOffset: mbrSignature}, expected: "ntfs"},
+		{name: "fat32", size: probeSize, img: image{fatOEMOffset: []byte("mkfs.fat"), fat32TypeOffset: []byte("FAT32   "), mbrSignatureOffset: mbrSignature}, expected: "fat"},
+		{name: "fat16", size: probeSize, img: image{fatOEMOffset: []byte("MSWIN4.1"), fat16TypeOffset: []byte("FAT16   "), mbrSignatureOffset: mbrSignature}, expected: "fat"},
+		{name: "gpt", size: probeSize, img: image{mbrPartitionOffset + mbrPartitionTypeOffset: {0xee}, mbrSignatureOffset: mbrSignature, gptHeaderOffset: gptMagic}, err: "GPT partition table"},
+		{name: "mbr", size: probeSize, img: image{mbrPartitionOffset + mbrPartitionEntrySize + mbrPartitionTypeOffset: {0x83}, mbrSignatureOffset: mbrSignature}, err: "MBR partition table"},
+		{name: "unknown", size: probeSize, img: image{4096: []byte("some data")}, err: "unknown data found"},
+		{name: "boot-sector-only", size: probeSize, img: image{mbrSignatureOffset: mbrSignature}, err: "unknown data found"},
+	}
+
+	for _, tt := range tests {
+		path := writeImage(t, dir, tt.name, tt.size, tt.img)
+
+		ft, err := probeFSType(path)
+		if tt.err != "" {
+			assert.Error(t, err, tt.err)
+			continue
+		}
+		assert.NilError(t, err)
+		assert.Equal(t, ft, tt.expected, tt.name)
+	}
+}
+
+func TestProbeFSTypeMissing(t *testing.T) {
+	_, err := probeFSType("/nonexistent/volume")
+	assert.Error(t, err, "no such file or directory")
+}
+
+// TestProbeFSTypeMkfs checks detection against filesystems created by the
+// real tools, when they are installed.
+func TestProbeFSTypeMkfs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "probe")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, fstype := range []string{"ext2", "ext3", "ext4", "xfs", "btrfs"} {
+		mkfs, err := exec.LookPath("mkfs." + fstype)
+		if err != nil {
+			continue
+		}
+
+		// xfs and btrfs refuse to create filesystems smaller than this.
+		path := writeImage(t, dir, fstype, 0, nil)
+		assert.NilError(t, os.Truncate(path, 300<<20))
+
+		args := []string{"-q", path}
+		switch fstype {
+		case "xfs", "btrfs":
+			args = []string{"-f", path}
+		}
+		if out, err := exec.CommandContext(context.Background(), mkfs, args...).CombinedOutput(); err != nil {
+			t.Logf("skipping %s: %v: %s", fstype, err, out)
+			continue
+		}
+
+		ft, err := probeFSType(path)
+		assert.NilError(t, err)
+		assert.Equal(t, ft, fstype)
+	}
+}
@@ -0,0 +1,120 @@
+package mount
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// resizeVolume waits for the device at path to reach size bytes, then grows
+// the filesystem mounted at mp to fill it. The usable size of the filesystem
+// is returned once it has been grown.
+func resizeVolume(ctx context.Context, path string, mp string, size uint64) (uint64, error) {
+
+	if err := waitForDeviceSize(ctx, path, size); err != nil {
+		return 0, err
+	}
+	log.Debugf("volume device grown: %s", path)
+
+	fsType, err := getVolumeFSType(path)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := growFilesystem(ctx, fsType, path, mp); err != nil {
+		log.WithFields(log.Fields{
+			"path":        path,
+			"mount_point": mp,
+			"fs_type":     fsType,
+			"error":       err,
+		}).Error("Resize failed")
+		return 0, err
+	}
+	log.Debugf("Resized filesystem: %s %s", path, mp)
+
+	return filesystemSize(mp)
+}
+
+// waitForDeviceSize waits for the device at path to be at least size bytes,
+// as the control plane grows the device asynchronously.
+func waitForDeviceSize(ctx context.Context, path string, size uint64) error {
+
+	var retries int
+	start := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("deadline exceeded while waiting for volume to grow")
+		default:
+			current, err := deviceSize(path)
+			if err != nil {
+				return err
+			}
+			if current >= size {
+				return nil
+			}
+
+			timeOff := backoff(retries)
+			log.Debugf("waiting for volume to grow: %s (%d/%d bytes), retrying in %v", path, current, size, timeOff)
+
+			if abort(start, timeOff) {
+				return fmt.Errorf("timed out waiting for volume to grow")
+			}
+
+			retries++
+			time.Sleep(timeOff)
+		}
+	}
+}
+
+// deviceSize returns the size in bytes of the device or image at path.
+func deviceSize(path string) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	end, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(end), nil
+}
+
+func growFilesystem(ctx context.Context, fsType string, path string, mp string) error {
+
+	var out string
+	var err error
+
+	// ext filesystems are grown through the device, xfs and btrfs through
+	// the mountpoint.
+	switch fsType {
+	case "ext2", "ext3", "ext4":
+		out, err = runCmd(ctx, resize2fs, path)
+	case "xfs":
+		out, err = runCmd(ctx, xfsGrowfs, mp)
+	case "btrfs":
+		out, err = runCmd(ctx, btrfs, "filesystem", "resize", "max", mp)
+	case rawFSType:
+		return fmt.Errorf("volume has no filesystem to resize")
+	default:
+		return fmt.Errorf("resizing %s filesystems is not supported", fsType)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"fstype": fsType,
+		"path":   path,
+		"output": out,
+	}).Debug("resized filesystem")
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package mount
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+func TestWaitForDeviceSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "resize")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "volume")
+	assert.NilError(t, ioutil.WriteFile(path, nil, 0600))
+	assert.NilError(t, os.Truncate(path, 2<<20))
+
+	// already large enough
+	assert.NilError(t, waitForDeviceSize(context.Background(), path, 1<<20))
+	assert.NilError(t, waitForDeviceSize(context.Background(), path, 2<<20))
+
+	// grows while waiting
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		os.Truncate(path, 4<<20)
+	}()
+	assert.NilError(t, waitForDeviceSize(context.Background(), path, 4<<20))
+
+	// never grows
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+	assert.Error(t, waitForDeviceSize(ctx, path, 8<<20), "deadline exceeded")
+
+	assert.Error(t, waitForDeviceSize(context.Background(), filepath.Join(dir, "missing"), 1), "no such file")
+}
+
+func TestGrowFilesystemUnsupported(t *testing.T) {
+	assert.Error(t, growFilesystem(context.Background(), rawFSType, "/dev/null", "/mnt"), "no filesystem")
+	assert.Error(t, growFilesystem(context.Background(), "ntfs", "/dev/null", "/mnt"), "not supported")
+}
+
+// TestGrowFilesystemExt grows an unmounted ext4 image, when the tools are
+// installed.
+func TestGrowFilesystemExt(t *testing.T) {
+	if _, err := exec.LookPath(resize2fs); err != nil {
+		t.Skip("resize2fs not installed")
+	}
+	if _, err := exec.LookPath(mkfsExt4); err != nil {
+		t.Skip("mkfs.ext4 not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "resize")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "volume")
+	assert.NilError(t, ioutil.WriteFile(path, nil, 0600))
+	assert.NilError(t, os.Truncate(path, 16<<20))
+
+	ctx := context.Background()
+	_, err = runCmd(ctx, mkfsExt4, "-q", "-F", path)
+	assert.NilError(t, err)
+	assert.NilError(t, os.Truncate(path, 32<<20))
+
+	assert.NilError(t, growFilesystem(ctx, "ext4", path, ""))
+	ft, err := getVolumeFSType(path)
+	assert.NilError(t, err)
+	assert.Equal(t, ft, "ext4")
+}
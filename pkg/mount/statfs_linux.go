@@ -0,0 +1,13 @@
+package mount
+
+import "syscall"
+
+// filesystemSize returns the usable size in bytes of the filesystem mounted
+// at mp.
+func filesystemSize(mp string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(mp, &st); err != nil {
+		return 0, err
+	}
+	return st.Blocks * uint64(st.Bsize), nil
+}
@@ -0,0 +1,10 @@
+// +build !linux
+
+package mount
+
+import "fmt"
+
+// filesystemSize is only supported on linux.
+func filesystemSize(mp string) (uint64, error) {
+	return 0, fmt.Errorf("filesystem size not supported on this platform")
+}
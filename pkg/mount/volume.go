@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/storageos/go-cli/pkg/validation"
 )
 
 // defaultTimeOut is the maximum time to wait for async volume operations.
@@ -16,23 +16,23 @@ const defaultTimeOut = 45
 
 // initRawVolume makes sure there is a raw volume at the path provided, and that
 // it's ready for use by Docker.  This includes creating a filesystem if there's
-// not one already present.
-func initRawVolume(ctx context.Context, path string, fsType string) error {
+// not one already present, passing mkfsOptions to mkfs.
+func initRawVolume(ctx context.Context, path string, fsType string, mkfsOptions []string) error {
 
 	if err := waitForVolume(ctx, path); err != nil {
 		return err
 	}
 	log.Debugf("volume found: %s", path)
 
-	ft, err := getVolumeFSType(ctx, path)
+	ft, err := getVolumeFSType(path)
 	log.Debugf("volume %s has fs type: %s", path, ft)
 	if err != nil {
 		return err
 	}
 
-	if ft == "raw" {
+	if ft == rawFSType {
 		log.Debugf("creating %s filesystem on volume %s", fsType, path)
-		if err := createFilesystem(ctx, fsType, path, ""); err != nil {
+		if err := createFilesystem(ctx, fsType, path, mkfsOptions); err != nil {
 			return err
 		}
 		log.Infof("%s filesystem created on volume %s", fsType, path)
@@ -74,56 +74,19 @@ func waitForVolume(ctx context.Context, path string) error {
 	}
 }
 
-// getVolumeFSType returns the volumes filesystem type if supported (currently
-// only ext4), "raw" if none, or an error if unsupported.  This may need
-// alteration for different OS flavours.
-func getVolumeFSType(ctx context.Context, path string) (string, error) {
-
-	// Run the file command against the path
-	// NOTE: -s returns same info for file and block devices. *DO NOT REMOVE*
-	out, err := runFile(ctx, "-s", path)
+// getVolumeFSType returns the volume's filesystem type, "raw" if the volume
+// is empty and can be formatted, or an error if it holds anything else.
+func getVolumeFSType(path string) (string, error) {
+	ft, err := probeFSType(path)
 	if err != nil {
 		return "", err
 	}
 
-	log.Debugf("checking volume for existing filesystem: %s: output: %s", path, out)
-	return parseFileOutput(path, out)
+	log.Debugf("checking volume for existing filesystem: %s: found: %s", path, ft)
+	return ft, nil
 }
 
-// parseFileOutput ttries to determine the volume's filesystem type or if it is
-// "raw" and can be formatted safely.
-func parseFileOutput(path string, out string) (string, error) {
-	switch {
-	case out == path+": data", out == path+": empty":
-		// At least on Ubuntu, file will report "/path/to/file: data" for a raw
-		// volume without a filesystem.  If this matches, we expect to be able to
-		// reformat. There seems to be a similar case for empty, we make the same
-		// assumptions for this too.
-		return "raw", nil
-	case strings.HasPrefix(out, path+": block special"):
-		// block special devices are reported on block devices when `file -s` is not
-		// used.  Leaving this check in "just in case", as we should never reformat
-		// a based on this info as it hides whether there is a filesystem.
-		return "", fmt.Errorf("detected block special device, aborting")
-	case strings.HasPrefix(out, path+": Linux rev 1.0 ext2 filesystem data"):
-		return "ext2", nil
-	case strings.HasPrefix(out, path+": Linux rev 1.0 ext3 filesystem data"):
-		return "ext3", nil
-	case strings.HasPrefix(out, path+": Linux rev 1.0 ext4 filesystem data"):
-		return "ext4", nil
-	case strings.HasPrefix(out, path+": SGI XFS filesystem data"):
-		return "xfs", nil
-	case strings.HasPrefix(out, path+": BTRFS Filesystem"):
-		return "btrfs", nil
-	case strings.HasPrefix(out, path+": DOS/MBR boot sector, code offset 0x58+2, OEM-ID \"mkfs.fat\""):
-		return "fat", nil
-	case strings.HasPrefix(out, path+": DOS/MBR boot sector, code offset 0x52+2, OEM-ID \"NTFS"):
-		return "ntfs", nil
-	}
-	return "", fmt.Errorf("unknown fs type: %s", out)
-}
-
-func createFilesystem(ctx context.Context, fstype string, path string, options string) error {
+func createFilesystem(ctx context.Context, fstype string, path string, options []string) error {
 
 	var retries int
 	start := time.Now()
@@ -156,37 +119,54 @@ func createFilesystem(ctx context.Context, fstype string, path string, options s
 	}
 }
 
-func runMkfs(ctx context.Context, fstype string, path string, options string) error {
+// runMkfs formats path. options are passed to mkfs after the defaults, once
+// checked against the options supported for the filesystem, which leave out
+// any that would override the UUID. The ext4 block size and extended options
+// are only defaults, replaced by those in options.
+func runMkfs(ctx context.Context, fstype string, path string, options []string) error {
 
 	var out string
 	var err error
 
+	if err := validation.IsValidMkfsArgs(fstype, options); err != nil {
+		return err
+	}
+	args := append([]string(nil), options...)
+
 	// Run mkfs
 	switch fstype {
 	case "ext2":
-		out, err = runCmd(ctx, mkfsExt2, path)
+		out, err = runCmd(ctx, mkfsExt2, append(args, path)...)
 		if err != nil {
 			return err
 		}
 	case "ext3":
-		out, err = runCmd(ctx, mkfsExt3, path)
+		out, err = runCmd(ctx, mkfsExt3, append(args, path)...)
 		if err != nil {
 			return err
 		}
 	case "ext4":
 		// Get the volume id from the path
 		id := getVolumeIDFromPath(path)
-		out, err = runCmd(ctx, mkfsExt4, "-F", "-U", id, "-b", "4096", "-E", "lazy_itable_init=0,lazy_journal_init=0", path)
+		defaults := []string{"-F", "-U", id}
+		// 4k blocks and no lazy initialisation, unless chosen otherwise.
+		if !validation.HasMkfsOption(fstype, args, "-b") {
+			defaults = append(defaults, "-b", "4096")
+		}
+		if !validation.HasMkfsOption(fstype, args, "-E") {
+			defaults = append(defaults, "-E", "lazy_itable_init=0,lazy_journal_init=0")
+		}
+		out, err = runCmd(ctx, mkfsExt4, append(append(defaults, args...), path)...)
 		if err != nil {
 			return err
 		}
 	case "xfs":
-		out, err = runCmd(ctx, mkfsXfs, path)
+		out, err = runCmd(ctx, mkfsXfs, append(args, path)...)
 		if err != nil {
 			return err
 		}
 	case "btrfs":
-		out, err = runCmd(ctx, mkfsBtrfs, path)
+		out, err = runCmd(ctx, mkfsBtrfs, append(args, path)...)
 		if err != nil {
 			return err
 		}
@@ -197,9 +177,10 @@ func runMkfs(ctx context.Context, fstype string, path string, options string) er
 	}
 
 	log.WithFields(log.Fields{
-		"fstype": fstype,
-		"path":   path,
-		"output": out,
+		"fstype":  fstype,
+		"path":    path,
+		"options": options,
+		"output":  out,
 	}).Debug("created filesystem")
 
 	return nil
@@ -0,0 +1,376 @@
+// Package selector parses label selectors and matches them against labels.
+//
+// A selector is a comma-separated list of requirements, all of which must
+// hold for a set of labels to match:
+//
+//	env, env exists        the label is set
+//	!env                   the label isn't set
+//	env=prod, env==prod    the label is set to prod
+//	env!=prod              the label isn't set to prod, or isn't set
+//	env in (prod,staging)  the label is set to one of the values
+//	env notin (dev,test)   the label isn't set to any of the values
+//	size>10, size gt 10    the label is an integer greater than 10
+//	size<10, size lt 10    the label is an integer less than 10
+//
+// The operators are those of types.Operator. An empty selector matches
+// everything.
+package selector
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/storageos/go-api/types"
+)
+
+// Requirement is a condition on the value of a label.
+type Requirement struct {
+	Key      string
+	Operator types.Operator
+	Values   []string
+}
+
+// Selector is a parsed label selector.
+type Selector []Requirement
+
+// Error is a syntax error in a selector. Pos is the 1-based position of
+// the character the error was found at.
+type Error struct {
+	Selector string
+	Pos      int
+	Msg      string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid selector %q: %s at position %d", e.Selector, e.Msg, e.Pos)
+}
+
+var (
+	// keyRE matches label keys: a name, optionally prefixed by a DNS
+	// subdomain and a slash, such as storageos.com/replicas.
+	keyRE = regexp.MustCompile(`^([a-z0-9]([-a-z0-9.]*[a-z0-9])?/)?[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+
+	// valueRE matches label values, which may be empty.
+	valueRE = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?)?$`)
+)
+
+// Parse parses a selector, returning an *Error if it is invalid.
+func Parse(s string) (Selector, error) {
+	p := &parser{s: s}
+	p.next()
+
+	var sel Selector
+	if p.tok == tokEOF {
+		return sel, nil
+	}
+	for {
+		r, err := p.requirement()
+		if err != nil {
+			return nil, err
+		}
+		sel = append(sel, r)
+
+		switch p.tok {
+		case tokEOF:
+			return sel, nil
+		case tokComma:
+			p.next()
+		default:
+			return nil, p.errorf("expected , or end of selector, found %s", p.describe())
+		}
+	}
+}
+
+// Matches returns true if labels satisfy every requirement of the selector.
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		if !r.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// Empty returns true if the selector has no requirements, and so matches
+// everything.
+func (s Selector) Empty() bool {
+	return len(s) == 0
+}
+
+// String returns the selector in its canonical form.
+func (s Selector) String() string {
+	parts := make([]string, len(s))
+	for i, r := range s {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// Matches returns true if labels satisfy the requirement.
+func (r Requirement) Matches(labels map[string]string) bool {
+	value, ok := labels[r.Key]
+	switch r.Operator {
+	case types.Exists:
+		return ok
+	case types.DoesNotExist:
+		return !ok
+	case types.Equals, types.DoubleEquals:
+		return ok && value == r.Values[0]
+	case types.NotEquals:
+		return !ok || value != r.Values[0]
+	case types.In:
+		return ok && contains(r.Values, value)
+	case types.NotIn:
+		return !ok || !contains(r.Values, value)
+	case types.GreaterThan, types.LessThan:
+		if !ok {
+			return false
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		limit, _ := strconv.ParseInt(r.Values[0], 10, 64)
+		if r.Operator == types.GreaterThan {
+			return n > limit
+		}
+		return n < limit
+	}
+	return false
+}
+
+// String returns the requirement in its canonical form.
+func (r Requirement) String() string {
+	switch r.Operator {
+	case types.Exists:
+		return r.Key
+	case types.DoesNotExist:
+		return "!" + r.Key
+	case types.In, types.NotIn:
+		return fmt.Sprintf("%s %s (%s)", r.Key, r.Operator, strings.Join(r.Values, ","))
+	case types.GreaterThan:
+		return r.Key + ">" + r.Values[0]
+	case types.LessThan:
+		return r.Key + "<" + r.Values[0]
+	}
+	return r.Key + string(r.Operator) + r.Values[0]
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type token int
+
+const (
+	tokEOF token = iota
+	tokWord
+	tokComma
+	tokOpen
+	tokClose
+	tokNot
+	tokEquals
+	tokDoubleEquals
+	tokNotEquals
+	tokGreater
+	tokLess
+)
+
+// parser reads a selector one token at a time. tok is the current token,
+// lit its text and pos its position.
+type parser struct {
+	s   string
+	off int
+
+	tok token
+	lit string
+	pos int
+}
+
+func isSpecial(c byte) bool {
+	return strings.IndexByte(",()=!<> \t", c) >= 0
+}
+
+func (p *parser) next() {
+	for p.off < len(p.s) && (p.s[p.off] == ' ' || p.s[p.off] == '\t') {
+		p.off++
+	}
+	p.pos = p.off + 1
+	if p.off == len(p.s) {
+		p.tok, p.lit = tokEOF, ""
+		return
+	}
+
+	start := p.off
+	switch c := p.s[p.off]; c {
+	case ',':
+		p.tok = tokComma
+	case '(':
+		p.tok = tokOpen
+	case ')':
+		p.tok = tokClose
+	case '>':
+		p.tok = tokGreater
+	case '<':
+		p.tok = tokLess
+	case '=':
+		p.tok = tokEquals
+		if strings.HasPrefix(p.s[p.off:], "==") {
+			p.tok = tokDoubleEquals
+			p.off++
+		}
+	case '!':
+		p.tok = tokNot
+		if strings.HasPrefix(p.s[p.off:], "!=") {
+			p.tok = tokNotEquals
+			p.off++
+		}
+	default:
+		for p.off < len(p.s) && !isSpecial(p.s[p.off]) {
+			p.off++
+		}
+		p.tok, p.lit = tokWord, p.s[start:p.off]
+		return
+	}
+	p.off++
+	p.lit = p.s[start:p.off]
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &Error{Selector: p.s, Pos: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// describe names the current token in errors.
+func (p *parser) describe() string {
+	if p.tok == tokEOF {
+		return "end of selector"
+	}
+	return fmt.Sprintf("%q", p.lit)
+}
+
+func (p *parser) key() (string, error) {
+	if p.tok != tokWord {
+		return "", p.errorf("expected a label key, found %s", p.describe())
+	}
+	if !keyRE.MatchString(p.lit) {
+		return "", p.errorf("invalid label key %q", p.lit)
+	}
+	key := p.lit
+	p.next()
+	return key, nil
+}
+
+// value reads a value, which may be empty when followed by allowEmpty.
+func (p *parser) value(allowEmpty ...token) (string, error) {
+	if p.tok != tokWord {
+		for _, tok := range allowEmpty {
+			if p.tok == tok {
+				return "", nil
+			}
+		}
+		return "", p.errorf("expected a value, found %s", p.describe())
+	}
+	if !valueRE.MatchString(p.lit) {
+		return "", p.errorf("invalid label value %q", p.lit)
+	}
+	value := p.lit
+	p.next()
+	return value, nil
+}
+
+func (p *parser) integer() (string, error) {
+	if p.tok != tokWord {
+		return "", p.errorf("expected an integer, found %s", p.describe())
+	}
+	if _, err := strconv.ParseInt(p.lit, 10, 64); err != nil {
+		return "", p.errorf("expected an integer, found %q", p.lit)
+	}
+	value := p.lit
+	p.next()
+	return value, nil
+}
+
+func (p *parser) requirement() (Requirement, error) {
+	if p.tok == tokNot {
+		p.next()
+		key, err := p.key()
+		return Requirement{Key: key, Operator: types.DoesNotExist}, err
+	}
+
+	key, err := p.key()
+	if err != nil {
+		return Requirement{}, err
+	}
+	r := Requirement{Key: key}
+
+	switch {
+	case p.tok == tokEOF, p.tok == tokComma:
+		r.Operator = types.Exists
+		return r, nil
+
+	case p.tok == tokWord && p.lit == string(types.Exists):
+		r.Operator = types.Exists
+		p.next()
+		return r, nil
+
+	case p.tok == tokEquals, p.tok == tokDoubleEquals, p.tok == tokNotEquals:
+		r.Operator = types.Operator(p.lit)
+		p.next()
+		value, err := p.value(tokEOF, tokComma)
+		r.Values = []string{value}
+		return r, err
+
+	case p.tok == tokGreater, p.tok == tokWord && p.lit == string(types.GreaterThan):
+		r.Operator = types.GreaterThan
+	case p.tok == tokLess, p.tok == tokWord && p.lit == string(types.LessThan):
+		r.Operator = types.LessThan
+
+	case p.tok == tokWord && (p.lit == string(types.In) || p.lit == string(types.NotIn)):
+		r.Operator = types.Operator(p.lit)
+		p.next()
+		r.Values, err = p.values()
+		return r, err
+
+	default:
+		return r, p.errorf("expected an operator, found %s", p.describe())
+	}
+
+	p.next()
+	value, err := p.integer()
+	r.Values = []string{value}
+	return r, err
+}
+
+// values reads a parenthesised, comma-separated list of values.
+func (p *parser) values() ([]string, error) {
+	if p.tok != tokOpen {
+		return nil, p.errorf("expected (, found %s", p.describe())
+	}
+	p.next()
+
+	var values []string
+	for {
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		switch p.tok {
+		case tokClose:
+			p.next()
+			return values, nil
+		case tokComma:
+			p.next()
+		default:
+			return nil, p.errorf("expected , or ), found %s", p.describe())
+		}
+	}
+}
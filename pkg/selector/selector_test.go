@@ -0,0 +1,83 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/storageos/go-cli/pkg/testutil/assert"
+)
+
+func TestParse(t *testing.T) {
+	for _, tt := range []struct {
+		selector string
+		expected string
+	}{
+		{selector: "", expected: ""},
+		{selector: "env", expected: "env"},
+		{selector: "env exists,tier", expected: "env,tier"},
+		{selector: " !env ", expected: "!env"},
+		{selector: "env==prod", expected: "env==prod"},
+		{selector: "env = prod,tier!=db", expected: "env=prod,tier!=db"},
+		{selector: "env=", expected: "env="},
+		{selector: "env in (prod, staging),tier notin (db)", expected: "env in (prod,staging),tier notin (db)"},
+		{selector: "storageos.com/replicas gt 1,size<10", expected: "storageos.com/replicas>1,size<10"},
+	} {
+		sel, err := Parse(tt.selector)
+		assert.NilError(t, err)
+		assert.Equal(t, sel.String(), tt.expected)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tt := range []struct {
+		selector string
+		err      string
+	}{
+		{selector: "env==prod,", err: `expected a label key, found end of selector at position 11`},
+		{selector: "env prod", err: `expected an operator, found "prod" at position 5`},
+		{selector: "env exists prod", err: `expected , or end of selector, found "prod" at position 12`},
+		{selector: "env in prod", err: `expected (, found "prod" at position 8`},
+		{selector: "env in (prod", err: `expected , or ), found end of selector at position 13`},
+		{selector: "env in ()", err: `expected a value, found ")" at position 9`},
+		{selector: "size>big", err: `expected an integer, found "big" at position 6`},
+		{selector: "env==prod)", err: `expected , or end of selector, found ")" at position 10`},
+		{selector: "-env", err: `invalid label key "-env" at position 1`},
+		{selector: "env==pr*d", err: `invalid label value "pr*d" at position 6`},
+	} {
+		_, err := Parse(tt.selector)
+		assert.Error(t, err, tt.err)
+		assert.Equal(t, err.(*Error).Selector, tt.selector)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	labels := map[string]string{"env": "prod", "tier": "db", "size": "20"}
+	for _, tt := range []struct {
+		selector string
+		expected bool
+	}{
+		{selector: "", expected: true},
+		{selector: "env", expected: true},
+		{selector: "env exists", expected: true},
+		{selector: "app exists", expected: false},
+		{selector: "!env", expected: false},
+		{selector: "!app", expected: true},
+		{selector: "env==prod,tier=db", expected: true},
+		{selector: "env==prod,tier=web", expected: false},
+		{selector: "env!=dev", expected: true},
+		{selector: "app!=web", expected: true},
+		{selector: "env in (dev,prod)", expected: true},
+		{selector: "app in (web)", expected: false},
+		{selector: "env notin (prod)", expected: false},
+		{selector: "app notin (web)", expected: true},
+		{selector: "size>10", expected: true},
+		{selector: "size lt 10", expected: false},
+		{selector: "env>10", expected: false},
+		{selector: "app<10", expected: false},
+	} {
+		sel, err := Parse(tt.selector)
+		assert.NilError(t, err)
+		if sel.Matches(labels) != tt.expected {
+			t.Errorf("%q: expected match %v", tt.selector, tt.expected)
+		}
+	}
+}
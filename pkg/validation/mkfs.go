@@ -0,0 +1,188 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// mkfsValue checks the value of an mkfs option. A nil mkfsValue means the
+// option takes no value.
+type mkfsValue func(value string) error
+
+func intRange(min, max int) mkfsValue {
+	return func(value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < min || n > max {
+			return fmt.Errorf("expected a number from %d to %d", min, max)
+		}
+		return nil
+	}
+}
+
+func powerOfTwo(min, max int) mkfsValue {
+	return func(value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < min || n > max || n&(n-1) != 0 {
+			return fmt.Errorf("expected a power of two from %d to %d", min, max)
+		}
+		return nil
+	}
+}
+
+func oneOf(values ...string) mkfsValue {
+	return func(value string) error {
+		for _, v := range values {
+			if value == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected one of %s", strings.Join(values, ", "))
+	}
+}
+
+func fsLabel(max int) mkfsValue {
+	return func(value string) error {
+		if value == "" || len(value) > max {
+			return fmt.Errorf("expected a label of 1 to %d bytes", max)
+		}
+		for _, r := range value {
+			if !unicode.IsPrint(r) {
+				return fmt.Errorf("label contains a non-printable character")
+			}
+		}
+		return nil
+	}
+}
+
+// subOptions checks a comma separated list of key=value pairs, such as the
+// values of the xfs -m option.
+func subOptions(keys map[string]mkfsValue) mkfsValue {
+	return func(value string) error {
+		for _, pair := range strings.Split(value, ",") {
+			i := strings.Index(pair, "=")
+			if i < 0 {
+				return fmt.Errorf("expected key=value pairs, got %q", pair)
+			}
+			check, ok := keys[pair[:i]]
+			if !ok {
+				return fmt.Errorf("%q not supported", pair[:i])
+			}
+			if err := check(pair[i+1:]); err != nil {
+				return fmt.Errorf("%s: %v", pair[:i], err)
+			}
+		}
+		return nil
+	}
+}
+
+var mkfsBool = oneOf("0", "1")
+
+var extMkfsOptions = map[string]mkfsValue{
+	"-b": powerOfTwo(1024, 65536),
+	"-E": subOptions(map[string]mkfsValue{
+		"lazy_itable_init":  mkfsBool,
+		"lazy_journal_init": mkfsBool,
+		"stride":            intRange(1, 1<<31-1),
+		"stripe_width":      intRange(1, 1<<31-1),
+	}),
+	"-i": intRange(1024, 67108864),
+	"-I": powerOfTwo(128, 4096),
+	"-m": intRange(0, 50),
+	"-N": intRange(1, 1<<31-1),
+	"-L": fsLabel(16),
+	"-T": oneOf("default", "floppy", "small", "big", "huge", "news", "largefile", "largefile4"),
+	"-q": nil,
+}
+
+// fsMkfsOptions lists the mkfs options accepted for each filesystem type.
+// Options that could override what the volume is formatted with by default,
+// such as the UUID, or that read from the host, such as the root directory
+// of mke2fs -d, are left out.
+var fsMkfsOptions = map[string]map[string]mkfsValue{
+	"ext2": extMkfsOptions,
+	"ext3": extMkfsOptions,
+	"ext4": extMkfsOptions,
+	"xfs": {
+		"-b": subOptions(map[string]mkfsValue{"size": powerOfTwo(512, 65536)}),
+		"-i": subOptions(map[string]mkfsValue{"size": powerOfTwo(256, 2048), "maxpct": intRange(0, 100)}),
+		"-m": subOptions(map[string]mkfsValue{"reflink": mkfsBool, "crc": mkfsBool, "finobt": mkfsBool}),
+		"-s": subOptions(map[string]mkfsValue{"size": powerOfTwo(512, 32768)}),
+		"-L": fsLabel(12),
+		"-q": nil,
+	},
+	"btrfs": {
+		"-n": powerOfTwo(4096, 65536),
+		"-s": powerOfTwo(4096, 65536),
+		"-m": oneOf("single", "dup"),
+		"-d": oneOf("single", "dup"),
+		"-L": fsLabel(255),
+		"-q": nil,
+	},
+}
+
+// ParseMkfsOptions returns the mkfs arguments for options, each an option
+// and its value if it takes one, such as "-b 4096" or "-L my label". The
+// value is everything after the first run of whitespace, so it may contain
+// spaces. The arguments are checked with IsValidMkfsArgs.
+func ParseMkfsOptions(fsType string, options []string) ([]string, error) {
+	var args []string
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		i := strings.IndexFunc(option, unicode.IsSpace)
+		if i < 0 {
+			args = append(args, option)
+			continue
+		}
+		args = append(args, option[:i], strings.TrimSpace(option[i:]))
+	}
+	if err := IsValidMkfsArgs(fsType, args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// HasMkfsOption returns true if the mkfs arguments, checked with
+// IsValidMkfsArgs, set option.
+func HasMkfsOption(fsType string, args []string, option string) bool {
+	supported := fsMkfsOptions[fsType]
+	for i := 0; i < len(args); i++ {
+		if args[i] == option {
+			return true
+		}
+		if supported[args[i]] != nil {
+			// Skip the value.
+			i++
+		}
+	}
+	return false
+}
+
+// IsValidMkfsArgs tests that the mkfs arguments only hold options supported
+// for the filesystem type, each followed by a valid value if it takes one.
+func IsValidMkfsArgs(fsType string, args []string) error {
+	if err := IsValidFSType(fsType); err != nil {
+		return err
+	}
+
+	supported := fsMkfsOptions[fsType]
+	for i := 0; i < len(args); i++ {
+		option := args[i]
+		check, ok := supported[option]
+		if !ok {
+			return fmt.Errorf("mkfs option %q not supported by %s", option, fsType)
+		}
+		if check == nil {
+			continue
+		}
+		if i+1 == len(args) {
+			return fmt.Errorf("mkfs option %q requires a value", option)
+		}
+		i++
+		if err := check(args[i]); err != nil {
+			return fmt.Errorf("invalid value %q for mkfs option %s: %v", args[i], option, err)
+		}
+	}
+	return nil
+}
@@ -28,14 +28,115 @@ func IsValidFSType(value string) error {
 	return fmt.Errorf("fs type not valid, available types: %s", strings.Join(ValidFSTypes, ", "))
 }
 
+// genericMountOptions are the mount options understood by every filesystem.
+// The value tells whether the option takes an argument. ro and rw are left
+// out as read-only mounts are requested separately.
+var genericMountOptions = map[string]bool{
+	"atime": false, "noatime": false, "relatime": false, "norelatime": false,
+	"strictatime": false, "nostrictatime": false, "diratime": false, "nodiratime": false,
+	"lazytime": false, "nolazytime": false, "dev": false, "nodev": false,
+	"exec": false, "noexec": false, "suid": false, "nosuid": false,
+	"sync": false, "async": false, "dirsync": false,
+}
+
+var extMountOptions = map[string]bool{
+	"acl": false, "noacl": false, "user_xattr": false, "nouser_xattr": false,
+	"errors": true,
+}
+
+var ext3MountOptions = map[string]bool{
+	"data": true, "commit": true, "barrier": false, "nobarrier": false,
+}
+
+var ext4MountOptions = map[string]bool{
+	"discard": false, "nodiscard": false, "delalloc": false, "nodelalloc": false,
+	"journal_checksum": false, "nojournal_checksum": false, "auto_da_alloc": false,
+	"noauto_da_alloc": false, "dioread_lock": false, "dioread_nolock": false,
+	"stripe": true, "inode_readahead_blks": true,
+}
+
+// fsMountOptions lists the mount options accepted for each filesystem type,
+// on top of the generic ones.
+var fsMountOptions = map[string][]map[string]bool{
+	"ext2": {extMountOptions},
+	"ext3": {extMountOptions, ext3MountOptions},
+	"ext4": {extMountOptions, ext3MountOptions, ext4MountOptions},
+	"xfs": {{
+		"discard": false, "nodiscard": false, "barrier": false, "nobarrier": false,
+		"inode32": false, "inode64": false, "largeio": false, "nolargeio": false,
+		"attr2": false, "noattr2": false, "nouuid": false, "wsync": false,
+		"noquota": false, "uquota": false, "gquota": false, "pquota": false,
+		"allocsize": true, "logbufs": true, "logbsize": true,
+	}},
+	"btrfs": {{
+		"discard": false, "nodiscard": false, "barrier": false, "nobarrier": false,
+		"ssd": false, "nossd": false, "autodefrag": false, "noautodefrag": false,
+		"datacow": false, "nodatacow": false, "datasum": false, "nodatasum": false,
+		"space_cache": false, "nospace_cache": false, "degraded": false,
+		"compress": false, "compress-force": false, "commit": true,
+		"subvol": true, "subvolid": true,
+	}},
+}
+
+// IsValidMountOption tests that the mount option is supported by the
+// filesystem type. Options taking a value must be given as key=value.
+func IsValidMountOption(fsType string, option string) error {
+	if err := IsValidFSType(fsType); err != nil {
+		return err
+	}
+
+	key, value, hasValue := option, "", false
+	if i := strings.Index(option, "="); i >= 0 {
+		key, value, hasValue = option[:i], option[i+1:], true
+	}
+
+	switch key {
+	case "ro", "rw":
+		return fmt.Errorf("mount option %q not allowed, use --read-only instead", option)
+	}
+
+	takesValue, ok := genericMountOptions[key]
+	for _, options := range fsMountOptions[fsType] {
+		if ok {
+			break
+		}
+		takesValue, ok = options[key]
+	}
+
+	// compress takes an optional algorithm.
+	if key == "compress" || key == "compress-force" {
+		takesValue = hasValue
+	}
+
+	switch {
+	case !ok:
+		return fmt.Errorf("mount option %q not supported by %s", key, fsType)
+	case takesValue && (!hasValue || value == ""):
+		return fmt.Errorf("mount option %q requires a value (%s=VALUE)", key, key)
+	case !takesValue && hasValue:
+		return fmt.Errorf("mount option %q does not take a value", key)
+	}
+	return nil
+}
+
+// DefaultNamespace is used for references without a namespace, when no
+// other namespace is chosen.
+const DefaultNamespace = "default"
+
 // ParseRefWithDefault wraps a call to the go-api's ParseRef
-// function, but adds default if the namespace is not defined.
+// function, but adds DefaultNamespace if the namespace is not defined.
 func ParseRefWithDefault(ref string) (string, string, error) {
-	namespace, name, err := storageos.ParseRef(ref)
+	return ParseRefInNamespace(ref, DefaultNamespace)
+}
+
+// ParseRefInNamespace wraps a call to the go-api's ParseRef
+// function, but adds namespace if the namespace is not defined.
+func ParseRefInNamespace(ref string, namespace string) (string, string, error) {
+	ns, name, err := storageos.ParseRef(ref)
 	if err != nil {
-		return storageos.ParseRef("default/" + ref)
+		return storageos.ParseRef(namespace + "/" + ref)
 	}
-	return namespace, name, err
+	return ns, name, err
 }
 
 // ParseHostPort returns a host:port string if the endpoint input is valid.
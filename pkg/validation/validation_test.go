@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -192,3 +193,91 @@ func TestParseHostPort(t *testing.T) {
 		})
 	}
 }
+
+func TestIsValidMountOption(t *testing.T) {
+	tests := []struct {
+		fsType  string
+		option  string
+		wantErr bool
+	}{
+		{fsType: "ext4", option: "noatime"},
+		{fsType: "ext4", option: "discard"},
+		{fsType: "ext4", option: "nobarrier"},
+		{fsType: "ext4", option: "data=writeback"},
+		{fsType: "ext3", option: "commit=30"},
+		{fsType: "ext2", option: "errors=remount-ro"},
+		{fsType: "xfs", option: "nodiscard"},
+		{fsType: "xfs", option: "logbufs=8"},
+		{fsType: "btrfs", option: "compress"},
+		{fsType: "btrfs", option: "compress=zstd"},
+		{fsType: "btrfs", option: "subvol=data"},
+		{fsType: "ext2", option: "discard", wantErr: true},
+		{fsType: "ext2", option: "nobarrier", wantErr: true},
+		{fsType: "xfs", option: "data=ordered", wantErr: true},
+		{fsType: "ext4", option: "data", wantErr: true},
+		{fsType: "ext4", option: "data=", wantErr: true},
+		{fsType: "ext4", option: "noatime=1", wantErr: true},
+		{fsType: "ext4", option: "ro", wantErr: true},
+		{fsType: "ext4", option: "rw", wantErr: true},
+		{fsType: "ext4", option: "foo", wantErr: true},
+		{fsType: "foo", option: "noatime", wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := IsValidMountOption(tt.fsType, tt.option); (err != nil) != tt.wantErr {
+			t.Errorf("IsValidMountOption(%q, %q) error = %v, wantErr %v", tt.fsType, tt.option, err, tt.wantErr)
+		}
+	}
+}
+
+func TestParseMkfsOptions(t *testing.T) {
+	tests := []struct {
+		fsType  string
+		options []string
+		want    []string
+		wantErr bool
+	}{
+		{fsType: "ext4", options: []string{"-i 8192", "-L my label"}, want: []string{"-i", "8192", "-L", "my label"}},
+		{fsType: "ext4", options: []string{"-q", "-m  1"}, want: []string{"-q", "-m", "1"}},
+		{fsType: "ext2", options: []string{"-b 2048"}, want: []string{"-b", "2048"}},
+		{fsType: "ext4", options: []string{"-b 1024", "-E lazy_itable_init=1,stride=16"}, want: []string{"-b", "1024", "-E", "lazy_itable_init=1,stride=16"}},
+		{fsType: "xfs", options: []string{"-m reflink=1,crc=1", "-b size=4096"}, want: []string{"-m", "reflink=1,crc=1", "-b", "size=4096"}},
+		{fsType: "btrfs", options: []string{"-d single", "-n 16384"}, want: []string{"-d", "single", "-n", "16384"}},
+		// Options that override the UUID or read from the host.
+		{fsType: "ext4", options: []string{"-E offset=4096"}, wantErr: true},
+		{fsType: "ext4", options: []string{"-E root_owner=0:0"}, wantErr: true},
+		{fsType: "ext4", options: []string{"-U 0000"}, wantErr: true},
+		{fsType: "ext4", options: []string{"-d /etc"}, wantErr: true},
+		{fsType: "ext4", options: []string{"-F"}, wantErr: true},
+		{fsType: "xfs", options: []string{"-m uuid=0000"}, wantErr: true},
+		{fsType: "xfs", options: []string{"-p /etc/proto"}, wantErr: true},
+		{fsType: "btrfs", options: []string{"-r /etc"}, wantErr: true},
+		// Bad values.
+		{fsType: "ext2", options: []string{"-b 3000"}, wantErr: true},
+		{fsType: "ext4", options: []string{"-b 131072"}, wantErr: true},
+		{fsType: "ext4", options: []string{"-i"}, wantErr: true},
+		{fsType: "ext4", options: []string{"-L a-label-longer-than-16"}, wantErr: true},
+		{fsType: "xfs", options: []string{"-m reflink=2"}, wantErr: true},
+		{fsType: "foo", options: []string{"-q"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMkfsOptions(tt.fsType, tt.options)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMkfsOptions(%q, %q) error = %v, wantErr %v", tt.fsType, tt.options, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseMkfsOptions(%q, %q) = %q, want %q", tt.fsType, tt.options, got, tt.want)
+		}
+	}
+}
+
+func TestHasMkfsOption(t *testing.T) {
+	// A label may look like an option.
+	args := []string{"-q", "-L", "-b", "-E", "stride=16"}
+	if HasMkfsOption("ext4", args, "-b") {
+		t.Errorf("HasMkfsOption(%q, -b) = true, want false", args)
+	}
+	if !HasMkfsOption("ext4", args, "-E") {
+		t.Errorf("HasMkfsOption(%q, -E) = false, want true", args)
+	}
+}